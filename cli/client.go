@@ -37,6 +37,7 @@ type RequestParams struct {
 	BaseAddress           string
 	Method                string
 	QueryParams           map[string][]string
+	Header                http.Header
 	Body                  interface{}
 	Out                   interface{}
 	CustomResponseDecoder ResponseDecoder
@@ -162,6 +163,139 @@ func (c *Client) FinishStream(ctx context.Context, streamUUID string) error {
 	return DoRequest(ctx, req)
 }
 
+// StreamLogs writes stream logs.
+func (c *Client) StreamLogs(ctx context.Context, streamUUID string, w io.Writer) error {
+	req := RequestParams{
+		Client:        c.httpClient,
+		BaseAddress:   c.baseAddress,
+		BasePath:      fmt.Sprintf("/stream/%s/logs", streamUUID),
+		Method:        http.MethodGet,
+		ExpStatusCode: http.StatusOK,
+		CustomResponseDecoder: func(r io.ReadCloser) error {
+			if _, err := io.Copy(w, r); err != nil {
+				return fmt.Errorf("could not read response body: %v", err)
+			}
+
+			return nil
+		},
+	}
+
+	return DoRequest(ctx, req)
+}
+
+// CreateTemplate creates a new stream template.
+func (c *Client) CreateTemplate(ctx context.Context, body models.StreamTemplateRequest) (
+	*models.StreamTemplateResponse, error) {
+	var resp models.StreamTemplateResponse
+	req := RequestParams{
+		Client:        c.httpClient,
+		BaseAddress:   c.baseAddress,
+		BasePath:      "/template",
+		Method:        http.MethodPost,
+		Body:          body,
+		Out:           &resp,
+		ExpStatusCode: http.StatusCreated,
+	}
+
+	if err := DoRequest(ctx, req); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// GetTemplates returns list of the stream templates.
+func (c *Client) GetTemplates(ctx context.Context) ([]models.StreamTemplateResponse, error) {
+	var resp []models.StreamTemplateResponse
+	req := RequestParams{
+		Client:        c.httpClient,
+		BaseAddress:   c.baseAddress,
+		BasePath:      "/template",
+		Method:        http.MethodGet,
+		Out:           &resp,
+		ExpStatusCode: http.StatusOK,
+	}
+
+	if err := DoRequest(ctx, req); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// GetTemplate returns stream template by name.
+func (c *Client) GetTemplate(ctx context.Context, name string) (
+	*models.StreamTemplateResponse, error) {
+	var resp models.StreamTemplateResponse
+	req := RequestParams{
+		Client:        c.httpClient,
+		BaseAddress:   c.baseAddress,
+		BasePath:      fmt.Sprintf("/template/%s", name),
+		Method:        http.MethodGet,
+		Out:           &resp,
+		ExpStatusCode: http.StatusOK,
+	}
+
+	if err := DoRequest(ctx, req); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// UpdateTemplate replaces existing stream template.
+func (c *Client) UpdateTemplate(ctx context.Context, body models.StreamTemplateRequest) (
+	*models.StreamTemplateResponse, error) {
+	var resp models.StreamTemplateResponse
+	req := RequestParams{
+		Client:        c.httpClient,
+		BaseAddress:   c.baseAddress,
+		BasePath:      fmt.Sprintf("/template/%s", body.Name),
+		Method:        http.MethodPut,
+		Body:          body,
+		Out:           &resp,
+		ExpStatusCode: http.StatusOK,
+	}
+
+	if err := DoRequest(ctx, req); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// DeleteTemplate deletes stream template by name.
+func (c *Client) DeleteTemplate(ctx context.Context, name string) error {
+	req := RequestParams{
+		Client:        c.httpClient,
+		BaseAddress:   c.baseAddress,
+		BasePath:      fmt.Sprintf("/template/%s", name),
+		Method:        http.MethodDelete,
+		ExpStatusCode: http.StatusOK,
+	}
+
+	return DoRequest(ctx, req)
+}
+
+// GetQuota returns usage of the stream quotas.
+func (c *Client) GetQuota(ctx context.Context) (*models.QuotaUsageResponse, error) {
+	var resp models.QuotaUsageResponse
+	req := RequestParams{
+		Client:        c.httpClient,
+		BaseAddress:   c.baseAddress,
+		BasePath:      "/quota",
+		Method:        http.MethodGet,
+		Out:           &resp,
+		ExpStatusCode: http.StatusOK,
+	}
+
+	if err := DoRequest(ctx, req); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
 // CreateStorageBackup creates storage backup.
 func (c *Client) CreateStorageBackup(ctx context.Context, storageName string, w io.Writer) error {
 	req := RequestParams{
@@ -208,10 +342,15 @@ func DoRequest(ctx context.Context, params RequestParams) error {
 	}
 	req.URL.RawQuery = q.Encode()
 
+	for name, values := range params.Header {
+		req.Header[name] = values
+	}
+
 	resp, err := params.Client.Do(req)
 	if err != nil {
 		return fmt.Errorf("could not do request: %v", err)
 	}
+	defer resp.Body.Close()
 
 	if resp.StatusCode != params.ExpStatusCode {
 		var srvErr middleware.Error
@@ -237,6 +376,14 @@ func DoRequest(ctx context.Context, params RequestParams) error {
 	return nil
 }
 
+// IsResponseError reports whether the error is responded by the server rather than
+// caused by failure to reach it.
+func IsResponseError(err error) bool {
+	_, ok := err.(middleware.Error)
+
+	return ok
+}
+
 // Address sets server API address option.
 func Address(address string) Option {
 	return func(o *Options) {
@@ -0,0 +1,206 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/code-cord/cc.core.server/handler/middleware"
+	"github.com/code-cord/cc.core.server/handler/models"
+	"github.com/code-cord/cc.core.server/service"
+)
+
+const (
+	authTokenHeader = "Authorization"
+	bearerPrefix    = "Bearer "
+)
+
+// WorkerClient represents stream worker http client implementation model.
+type WorkerClient struct {
+	baseAddress string
+	token       string
+	httpClient  *http.Client
+}
+
+// WorkerClientConfig represents worker client configuration model.
+type WorkerClientConfig struct {
+	Address    string
+	Token      string
+	TLSEnabled bool
+	HTTPClient *http.Client
+}
+
+// NewWorkerClient returns new worker client instance.
+//
+// The token is the one shared by the server and the worker. If TLS is enabled,
+// the worker API is requested over https with the http client of the config.
+func NewWorkerClient(cfg WorkerClientConfig) WorkerClient {
+	scheme := "http"
+	if cfg.TLSEnabled {
+		scheme = "https"
+	}
+
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	return WorkerClient{
+		baseAddress: fmt.Sprintf("%s://%s", scheme, cfg.Address),
+		token:       cfg.Token,
+		httpClient:  httpClient,
+	}
+}
+
+// Info returns worker info.
+func (c *WorkerClient) Info(ctx context.Context) (*service.WorkerInfo, error) {
+	var out models.WorkerInfoResponse
+	req := c.requestParams(http.MethodGet, "/", http.StatusOK)
+	req.Out = &out
+
+	if err := DoRequest(ctx, req); err != nil {
+		return nil, err
+	}
+
+	return &service.WorkerInfo{
+		Name:       out.Name,
+		Version:    out.Version,
+		LaunchMode: out.LaunchMode,
+		Capacity:   out.Capacity,
+		Streams:    out.Streams,
+	}, nil
+}
+
+// StartStream starts a new stream on the worker.
+//
+// It returns service.ErrWorkerFull if the worker can't run one more stream.
+func (c *WorkerClient) StartStream(
+	ctx context.Context, streamUUID string, cfg service.StreamLaunchConfig) (
+	*service.StartStreamInfo, error) {
+	body := models.WorkerStartStreamRequest{
+		UUID:          streamUUID,
+		PreferredPort: cfg.PreferredPort,
+		PreferredIP:   cfg.PreferredIP,
+		Env:           cfg.Env,
+		Args:          cfg.Args,
+		Image:         cfg.Image,
+	}
+	if cfg.Limits != (service.StreamResourceLimits{}) {
+		body.Limits = &models.StreamLimitsRequest{
+			CPU:    cfg.Limits.CPU,
+			Memory: cfg.Limits.Memory,
+			PIDs:   cfg.Limits.PIDs,
+		}
+	}
+
+	var out models.WorkerStreamResponse
+	req := c.requestParams(http.MethodPost, "/stream", http.StatusCreated)
+	req.Body = body
+	req.Out = &out
+
+	if err := DoRequest(ctx, req); err != nil {
+		if srvErr, ok := err.(middleware.Error); ok && srvErr.Code == middleware.ErrWorkerFull.Code {
+			return nil, service.ErrWorkerFull
+		}
+
+		return nil, err
+	}
+
+	return &service.StartStreamInfo{
+		IP:   out.IP,
+		Port: out.Port,
+	}, nil
+}
+
+// WorkerStream returns info of the stream run by the worker.
+//
+// It returns os.ErrNotExist if the worker doesn't run the stream.
+func (c *WorkerClient) WorkerStream(ctx context.Context, streamUUID string) (
+	*service.StartStreamInfo, error) {
+	var out models.WorkerStreamResponse
+	req := c.requestParams(http.MethodGet, fmt.Sprintf("/stream/%s", streamUUID), http.StatusOK)
+	req.Out = &out
+
+	if err := DoRequest(ctx, req); err != nil {
+		if srvErr, ok := err.(middleware.Error); ok && srvErr.Code == middleware.ErrWorkerStream.Code {
+			return nil, os.ErrNotExist
+		}
+
+		return nil, err
+	}
+
+	return &service.StartStreamInfo{
+		IP:   out.IP,
+		Port: out.Port,
+	}, nil
+}
+
+// StopStream stops the stream run by the worker.
+func (c *WorkerClient) StopStream(ctx context.Context, streamUUID string) error {
+	req := c.requestParams(http.MethodDelete, fmt.Sprintf("/stream/%s", streamUUID), http.StatusOK)
+
+	return DoRequest(ctx, req)
+}
+
+// PauseStream pauses the stream run by the worker.
+func (c *WorkerClient) PauseStream(ctx context.Context, streamUUID string) error {
+	req := c.requestParams(
+		http.MethodPost, fmt.Sprintf("/stream/%s/pause", streamUUID), http.StatusOK)
+
+	return DoRequest(ctx, req)
+}
+
+// ResumeStream resumes the stream run by the worker.
+func (c *WorkerClient) ResumeStream(ctx context.Context, streamUUID string) error {
+	req := c.requestParams(
+		http.MethodPost, fmt.Sprintf("/stream/%s/resume", streamUUID), http.StatusOK)
+
+	return DoRequest(ctx, req)
+}
+
+// StreamLogs writes output of the stream run by the worker to w until the stream exits.
+func (c *WorkerClient) StreamLogs(ctx context.Context, streamUUID string, w io.Writer) error {
+	req := c.requestParams(
+		http.MethodGet, fmt.Sprintf("/stream/%s/logs", streamUUID), http.StatusOK)
+	req.CustomResponseDecoder = func(r io.ReadCloser) error {
+		if _, err := io.Copy(w, r); err != nil {
+			return fmt.Errorf("could not read response body: %v", err)
+		}
+
+		return nil
+	}
+
+	return DoRequest(ctx, req)
+}
+
+// WaitStream waits for the stream run by the worker to exit.
+func (c *WorkerClient) WaitStream(ctx context.Context, streamUUID string) (
+	*service.WorkerStreamExit, error) {
+	var out models.WorkerStreamExitResponse
+	req := c.requestParams(
+		http.MethodGet, fmt.Sprintf("/stream/%s/wait", streamUUID), http.StatusOK)
+	req.Out = &out
+
+	if err := DoRequest(ctx, req); err != nil {
+		return nil, err
+	}
+
+	return &service.WorkerStreamExit{
+		Error: out.Error,
+	}, nil
+}
+
+func (c *WorkerClient) requestParams(method, path string, expStatusCode int) RequestParams {
+	return RequestParams{
+		Client:      c.httpClient,
+		BaseAddress: c.baseAddress,
+		BasePath:    path,
+		Method:      method,
+		Header: http.Header{
+			authTokenHeader: []string{bearerPrefix + c.token},
+		},
+		ExpStatusCode: expStatusCode,
+	}
+}
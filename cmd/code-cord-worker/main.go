@@ -0,0 +1,206 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/code-cord/cc.core.server/service"
+	"github.com/code-cord/cc.core.server/worker"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	codeCordBinPathEnv     = "CODE_CORD_PATH"
+	codeCordWorkerTokenEnv = "CODE_CORD_WORKER_TOKEN"
+
+	defaultWorkerName            = "code-cord worker"
+	defaultWorkerVersion         = "0.0.1"
+	defaultStreamPrefixContainer = "code-cord.stream"
+	defaultStreamImage           = "code-cord.stream"
+)
+
+type workerConfig struct {
+	address               string
+	tlsCertFilePath       string
+	tlsKeyFilePath        string
+	token                 string
+	dataFolder            string
+	logLevel              string
+	launchMode            string
+	capacity              int
+	binariesPath          string
+	streamImage           string
+	streamContainerPrefix string
+	streamIP              string
+	streamStopTimeout     time.Duration
+	streamCgroupParent    string
+	streamPortRange       string
+}
+
+func main() {
+	var cfg workerConfig
+
+	app := &cli.App{
+		Name:  "code-cord-worker",
+		Usage: "run code-cord streams on behalf of the stream server",
+		Action: func(c *cli.Context) error {
+			return nil
+		},
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name: "address",
+				Aliases: []string{
+					"addr",
+					"a",
+				},
+				Usage:       "Worker listen and serve address",
+				DefaultText: "127.0.0.1:8990",
+				Destination: &cfg.address,
+				Required:    false,
+			},
+			&cli.PathFlag{
+				Name:        "tls-cert",
+				Usage:       "TLS cert file path (for https connections)",
+				Required:    false,
+				Destination: &cfg.tlsCertFilePath,
+			},
+			&cli.PathFlag{
+				Name:        "tls-key",
+				Usage:       "TLS key file path (for https connections)",
+				Required:    false,
+				Destination: &cfg.tlsKeyFilePath,
+			},
+			&cli.PathFlag{
+				Name:        "data-folder",
+				Usage:       "Data folder to store worker data with read and write permissions",
+				Required:    false,
+				Destination: &cfg.dataFolder,
+				DefaultText: "./.worker",
+			},
+			&cli.StringFlag{
+				Name:        "token",
+				Usage:       "Token shared with the stream server to authorize its requests",
+				Required:    true,
+				Destination: &cfg.token,
+				EnvVars: []string{
+					codeCordWorkerTokenEnv,
+				},
+			},
+			&cli.StringFlag{
+				Name:        "log-level",
+				Usage:       "Worker log level",
+				Required:    false,
+				Destination: &cfg.logLevel,
+				DefaultText: "info",
+			},
+			&cli.StringFlag{
+				Name:        "launch",
+				Usage:       "Mode the worker launches streams in (standalone_app or docker_container)",
+				Required:    false,
+				Destination: &cfg.launchMode,
+				DefaultText: string(service.StreamLaunchModeStandaloneApp),
+			},
+			&cli.IntFlag{
+				Name:        "capacity",
+				Usage:       "Max number of streams run by the worker at the same time",
+				Required:    false,
+				Destination: &cfg.capacity,
+				DefaultText: "unlimited",
+			},
+			&cli.PathFlag{
+				Name:        "bin",
+				Usage:       "Path to the code-cord binaries folder",
+				Required:    false,
+				Destination: &cfg.binariesPath,
+				EnvVars: []string{
+					codeCordBinPathEnv,
+				},
+			},
+			&cli.StringFlag{
+				Name:        "stream-image",
+				Usage:       "Docker image of the stream",
+				Required:    false,
+				Value:       defaultStreamImage,
+				Destination: &cfg.streamImage,
+			},
+			&cli.StringFlag{
+				Name:        "stream-container-prefix",
+				Usage:       "Name prefix of the stream docker containers",
+				Required:    false,
+				Value:       defaultStreamPrefixContainer,
+				Destination: &cfg.streamContainerPrefix,
+			},
+			&cli.StringFlag{
+				Name:        "stream-ip",
+				Usage:       "IP the streams are bound to, it must be reachable by the server",
+				Required:    false,
+				Destination: &cfg.streamIP,
+				DefaultText: "127.0.0.1 for standalone streams, 0.0.0.0 for docker ones",
+			},
+			&cli.DurationFlag{
+				Name:        "stream-stop-timeout",
+				Usage:       "Grace period for the stream to exit before it is killed",
+				Required:    false,
+				Destination: &cfg.streamStopTimeout,
+				DefaultText: "10s",
+			},
+			&cli.StringFlag{
+				Name:        "stream-cgroup-parent",
+				Usage:       "Parent cgroup of standalone streams relative to the cgroup v2 root",
+				Required:    false,
+				Destination: &cfg.streamCgroupParent,
+				DefaultText: "code-cord",
+			},
+			&cli.StringFlag{
+				Name:        "stream-port-range",
+				Usage:       "Range of the ports in min-max format the streams are bound to",
+				Required:    false,
+				Destination: &cfg.streamPortRange,
+				DefaultText: "any free port",
+			},
+		},
+	}
+	if err := app.Run(os.Args); err != nil {
+		logrus.Fatalf("could not start app: %v", err)
+	}
+
+	w, err := worker.New(
+		worker.Name(defaultWorkerName),
+		worker.Version(defaultWorkerVersion),
+		worker.Address(cfg.address),
+		worker.TLS(cfg.tlsCertFilePath, cfg.tlsKeyFilePath),
+		worker.Token(cfg.token),
+		worker.DataFolder(cfg.dataFolder),
+		worker.LogLevel(cfg.logLevel),
+		worker.LaunchMode(service.StreamLaunchMode(cfg.launchMode)),
+		worker.Capacity(cfg.capacity),
+		worker.BinFolder(cfg.binariesPath),
+		worker.StreamImage(cfg.streamImage),
+		worker.StreamContainerPrefix(cfg.streamContainerPrefix),
+		worker.StreamIP(cfg.streamIP),
+		worker.StreamStopTimeout(cfg.streamStopTimeout),
+		worker.StreamCgroupParent(cfg.streamCgroupParent),
+		worker.StreamPortRange(cfg.streamPortRange),
+	)
+	if err != nil {
+		logrus.Fatalf("could not create worker instance: %v", err)
+	}
+
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-c
+		logrus.Warn("worker is shutting down...")
+		if err := w.Stop(context.Background()); err != nil {
+			logrus.Errorf("could not stop worker: %v", err)
+		}
+	}()
+
+	if err := w.Run(context.Background()); err != nil {
+		logrus.Fatalf("could not run worker: %v", err)
+	}
+}
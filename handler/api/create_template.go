@@ -0,0 +1,105 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/code-cord/cc.core.server/handler/middleware"
+	"github.com/code-cord/cc.core.server/handler/models"
+	"github.com/code-cord/cc.core.server/service"
+)
+
+func (h *Router) createTemplate(w http.ResponseWriter, r *http.Request) {
+	var req models.StreamTemplateRequest
+	if err := middleware.ParseJSONRequest(r, &req); err != nil {
+		middleware.WriteJSONResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	tpl := buildStreamTemplate(&req)
+	if err := h.server.NewStreamTemplate(r.Context(), tpl); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrInvalidLaunchMode) ||
+			errors.Is(err, service.ErrForbiddenStreamEnv) ||
+			errors.Is(err, service.ErrForbiddenStreamArg) {
+			status = http.StatusBadRequest
+		}
+
+		middleware.WriteJSONResponse(w, status, middleware.ErrCreateTemplate.New(err.Error()))
+		return
+	}
+
+	middleware.WriteJSONResponse(w, http.StatusCreated, buildStreamTemplateResponse(&tpl))
+}
+
+func buildStreamTemplate(req *models.StreamTemplateRequest) service.StreamTemplate {
+	tpl := service.StreamTemplate{
+		Name:        req.Name,
+		Description: req.Description,
+		Join: service.StreamJoinPolicyConfig{
+			JoinPolicy: req.Join.Policy,
+			JoinCode:   req.Join.Code,
+		},
+		Launch: service.StreamLaunchConfig{
+			PreferredPort: req.Stream.PreferredPort,
+			PreferredIP:   req.Stream.PreferredIP,
+			Mode:          req.Stream.LaunchMode,
+			Env:           req.Stream.Env,
+			Args:          req.Stream.Args,
+			Image:         req.Stream.Image,
+		},
+		MaxDuration: time.Duration(req.Stream.MaxDuration) * time.Second,
+		IdleTimeout: time.Duration(req.Stream.IdleTimeout) * time.Second,
+	}
+	if req.Stream.Restart != nil {
+		tpl.Launch.Restart = service.StreamRestartConfig{
+			Policy:      req.Stream.Restart.Policy,
+			MaxAttempts: req.Stream.Restart.MaxAttempts,
+		}
+	}
+	if req.Stream.Limits != nil {
+		tpl.Launch.Limits = service.StreamResourceLimits{
+			CPU:    req.Stream.Limits.CPU,
+			Memory: req.Stream.Limits.Memory,
+			PIDs:   req.Stream.Limits.PIDs,
+		}
+	}
+
+	return tpl
+}
+
+func buildStreamTemplateResponse(tpl *service.StreamTemplate) models.StreamTemplateResponse {
+	resp := models.StreamTemplateResponse{
+		Name:        tpl.Name,
+		Description: tpl.Description,
+		Join: models.StreamTemplateJoinResponse{
+			Policy: tpl.Join.JoinPolicy,
+			Code:   tpl.Join.JoinCode,
+		},
+		Stream: models.StreamTemplateConfigResponse{
+			PreferredPort: tpl.Launch.PreferredPort,
+			PreferredIP:   tpl.Launch.PreferredIP,
+			LaunchMode:    tpl.Launch.Mode,
+			Limits: models.StreamLimitsResponse{
+				CPU:    tpl.Launch.Limits.CPU,
+				Memory: tpl.Launch.Limits.Memory,
+				PIDs:   tpl.Launch.Limits.PIDs,
+			},
+			MaxDuration: int(tpl.MaxDuration / time.Second),
+			IdleTimeout: int(tpl.IdleTimeout / time.Second),
+			Env:         tpl.Launch.Env,
+			Args:        tpl.Launch.Args,
+			Image:       tpl.Launch.Image,
+		},
+	}
+
+	if tpl.Launch.Restart.Policy != "" {
+		resp.Stream.Restart = &models.StreamRestartResponse{
+			Policy:      tpl.Launch.Restart.Policy,
+			MaxAttempts: tpl.Launch.Restart.MaxAttempts,
+		}
+	}
+
+	return resp
+}
@@ -0,0 +1,25 @@
+package api
+
+import (
+	"net/http"
+	"os"
+
+	"github.com/code-cord/cc.core.server/handler/middleware"
+	"github.com/gorilla/mux"
+)
+
+func (h *Router) deleteTemplate(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+
+	if err := h.server.DeleteStreamTemplate(r.Context(), name); err != nil {
+		status := http.StatusInternalServerError
+		if os.IsNotExist(err) {
+			status = http.StatusNotFound
+		}
+
+		middleware.WriteJSONResponse(w, status, middleware.ErrDeleteTemplate.New(err.Error()))
+		return
+	}
+
+	middleware.WriteJSONResponse(w, http.StatusOK, nil)
+}
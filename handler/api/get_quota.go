@@ -0,0 +1,38 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/code-cord/cc.core.server/handler/middleware"
+	"github.com/code-cord/cc.core.server/handler/models"
+)
+
+func (h *Router) getQuota(w http.ResponseWriter, r *http.Request) {
+	usage, err := h.server.QuotaUsage(r.Context())
+	if err != nil {
+		middleware.WriteJSONResponse(w, http.StatusInternalServerError,
+			middleware.ErrQuotaUsage.New(err.Error()))
+		return
+	}
+
+	resp := models.QuotaUsageResponse{
+		Limits: models.StreamQuotaResponse{
+			MaxStreams:           usage.Limits.MaxStreams,
+			MaxSubjectStreams:    usage.Limits.MaxSubjectStreams,
+			MaxSubjectStreamTime: int(usage.Limits.MaxSubjectStreamTime / time.Second),
+			Period:               int(usage.Limits.Period / time.Second),
+		},
+		Running:  usage.Running,
+		Subjects: make([]models.SubjectQuotaUsageResponse, len(usage.Subjects)),
+	}
+	for i := range usage.Subjects {
+		resp.Subjects[i] = models.SubjectQuotaUsageResponse{
+			Subject:    usage.Subjects[i].Subject,
+			Running:    usage.Subjects[i].Running,
+			StreamTime: int(usage.Subjects[i].StreamTime / time.Second),
+		}
+	}
+
+	middleware.WriteJSONResponse(w, http.StatusOK, resp)
+}
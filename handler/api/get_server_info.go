@@ -22,5 +22,6 @@ func buildServerInfoResponse(s service.Server) models.ServerInfoResponse {
 		Description:    info.Description,
 		Version:        info.Version,
 		AdditionalInfo: info.Meta,
+		LaunchModes:    info.LaunchModes,
 	}
 }
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/code-cord/cc.core.server/handler/middleware"
+	"github.com/code-cord/cc.core.server/handler/models"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	maxStreamLogLineSize = 1 << 20
+)
+
+func (h *Router) streamLogs(w http.ResponseWriter, r *http.Request) {
+	var req models.StreamLogsRequest
+	if err := middleware.ParseURLRequest(r, &req); err != nil {
+		middleware.WriteJSONResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	streamUUID := mux.Vars(r)["uuid"]
+
+	logs, err := h.server.StreamLogs(r.Context(), streamUUID, req.Follow)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if os.IsNotExist(err) {
+			status = http.StatusNotFound
+		}
+
+		middleware.WriteJSONResponse(w, status, middleware.ErrStreamLogs.New(err.Error()))
+		return
+	}
+	defer logs.Close()
+
+	if !req.Follow {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		if _, err := io.Copy(w, logs); err != nil {
+			logrus.Errorf("could not write %s stream logs: %v", streamUUID, err)
+		}
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		middleware.WriteJSONResponse(w, http.StatusBadRequest, middleware.ErrSSEUpgrade.New(nil))
+		return
+	}
+	middleware.UpgradeRequestToSSE(w, "*")
+	flusher.Flush()
+
+	scanner := bufio.NewScanner(logs)
+	scanner.Buffer(nil, maxStreamLogLineSize)
+	for scanner.Scan() {
+		if _, err := fmt.Fprintf(w, "data: %s\n\n", scanner.Text()); err != nil {
+			return
+		}
+		flusher.Flush()
+	}
+
+	if err := scanner.Err(); err != nil && r.Context().Err() == nil {
+		logrus.Errorf("could not read %s stream logs: %v", streamUUID, err)
+	}
+}
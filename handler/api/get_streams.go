@@ -66,6 +66,16 @@ func buildStreamListResponse(streams *service.StreamList) models.StreamListRespo
 				AvatarID: stream.Host.AvatarID,
 				IP:       stream.Host.IP,
 			},
+			Restarts:    stream.Restarts,
+			Health:      stream.Health,
+			ScheduledAt: stream.ScheduledAt,
+			Limits: models.StreamLimitsResponse{
+				CPU:    stream.Limits.CPU,
+				Memory: stream.Limits.Memory,
+				PIDs:   stream.Limits.PIDs,
+			},
+			Worker: stream.Worker,
+			Error:  stream.Error,
 		}
 	}
 
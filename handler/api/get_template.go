@@ -0,0 +1,26 @@
+package api
+
+import (
+	"net/http"
+	"os"
+
+	"github.com/code-cord/cc.core.server/handler/middleware"
+	"github.com/gorilla/mux"
+)
+
+func (h *Router) getTemplate(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+
+	tpl, err := h.server.StreamTemplate(r.Context(), name)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if os.IsNotExist(err) {
+			status = http.StatusNotFound
+		}
+
+		middleware.WriteJSONResponse(w, status, middleware.ErrFetchTemplate.New(err.Error()))
+		return
+	}
+
+	middleware.WriteJSONResponse(w, http.StatusOK, buildStreamTemplateResponse(tpl))
+}
@@ -0,0 +1,24 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/code-cord/cc.core.server/handler/middleware"
+	"github.com/code-cord/cc.core.server/handler/models"
+)
+
+func (h *Router) getTemplates(w http.ResponseWriter, r *http.Request) {
+	templates, err := h.server.StreamTemplates(r.Context())
+	if err != nil {
+		middleware.WriteJSONResponse(w, http.StatusInternalServerError,
+			middleware.ErrTemplateList.New(err.Error()))
+		return
+	}
+
+	resp := make([]models.StreamTemplateResponse, len(templates))
+	for i := range templates {
+		resp[i] = buildStreamTemplateResponse(&templates[i])
+	}
+
+	middleware.WriteJSONResponse(w, http.StatusOK, resp)
+}
@@ -44,6 +44,34 @@ func New(cfg Config) Router {
 		Methods(http.MethodDelete).
 		HandlerFunc(r.finishStream)
 
+	r.Path("/stream/{uuid}/logs").
+		Methods(http.MethodGet).
+		HandlerFunc(r.streamLogs)
+
+	r.Path("/template").
+		Methods(http.MethodPost).
+		HandlerFunc(r.createTemplate)
+
+	r.Path("/template").
+		Methods(http.MethodGet).
+		HandlerFunc(r.getTemplates)
+
+	r.Path("/template/{name}").
+		Methods(http.MethodGet).
+		HandlerFunc(r.getTemplate)
+
+	r.Path("/template/{name}").
+		Methods(http.MethodPut).
+		HandlerFunc(r.updateTemplate)
+
+	r.Path("/template/{name}").
+		Methods(http.MethodDelete).
+		HandlerFunc(r.deleteTemplate)
+
+	r.Path("/quota").
+		Methods(http.MethodGet).
+		HandlerFunc(r.getQuota)
+
 	r.Path("/storage/{name}").
 		Methods(http.MethodGet).
 		HandlerFunc(r.storageBackup)
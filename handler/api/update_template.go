@@ -0,0 +1,50 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"os"
+
+	"github.com/code-cord/cc.core.server/handler/middleware"
+	"github.com/code-cord/cc.core.server/handler/models"
+	"github.com/code-cord/cc.core.server/service"
+	"github.com/gorilla/mux"
+)
+
+func (h *Router) updateTemplate(w http.ResponseWriter, r *http.Request) {
+	var req models.StreamTemplateRequest
+	if err := middleware.ParseJSONRequest(r, &req); err != nil {
+		middleware.WriteJSONResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	name := mux.Vars(r)["name"]
+	if req.Name != name {
+		middleware.WriteJSONResponse(w, http.StatusBadRequest,
+			middleware.ErrInvalidRequestParam.New([]middleware.RequestParamErrDetails{
+				{
+					Param:  "name",
+					Errors: []string{"must match the template name in the path"},
+				},
+			}))
+		return
+	}
+
+	tpl := buildStreamTemplate(&req)
+	if err := h.server.UpdateStreamTemplate(r.Context(), tpl); err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case os.IsNotExist(err):
+			status = http.StatusNotFound
+		case errors.Is(err, service.ErrInvalidLaunchMode),
+			errors.Is(err, service.ErrForbiddenStreamEnv),
+			errors.Is(err, service.ErrForbiddenStreamArg):
+			status = http.StatusBadRequest
+		}
+
+		middleware.WriteJSONResponse(w, status, middleware.ErrUpdateTemplate.New(err.Error()))
+		return
+	}
+
+	middleware.WriteJSONResponse(w, http.StatusOK, buildStreamTemplateResponse(&tpl))
+}
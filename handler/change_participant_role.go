@@ -0,0 +1,32 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/code-cord/cc.core.server/handler/middleware"
+	"github.com/code-cord/cc.core.server/handler/models"
+	"github.com/gorilla/mux"
+)
+
+func (h *Router) changeParticipantRole(w http.ResponseWriter, r *http.Request) {
+	var req models.ChangeParticipantRoleRequest
+	if err := middleware.ParseJSONRequest(r, &req); err != nil {
+		middleware.WriteJSONResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	vars := mux.Vars(r)
+	streamUUID := vars["uuid"]
+	participantUUID := vars["participantUUID"]
+
+	participant, err := h.server.ChangeParticipantRole(
+		r.Context(), streamUUID, participantUUID, req.Role)
+	if err != nil {
+		middleware.WriteJSONResponse(w, http.StatusInternalServerError,
+			middleware.ErrChangeParticipantRole.New(err.Error()))
+		return
+	}
+
+	resp := buildStreamParticipantResponse(participant)
+	middleware.WriteJSONResponse(w, http.StatusOK, resp)
+}
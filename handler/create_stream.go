@@ -1,7 +1,10 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/code-cord/cc.core.server/handler/middleware"
 	"github.com/code-cord/cc.core.server/handler/models"
@@ -21,7 +24,7 @@ func (h *Router) createStream(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	streamInfo, err := h.server.NewStream(r.Context(), service.StreamConfig{
+	cfg := service.StreamConfig{
 		Name:        req.Name,
 		Description: req.Description,
 		Join: service.StreamJoinPolicyConfig{
@@ -32,14 +35,78 @@ func (h *Router) createStream(w http.ResponseWriter, r *http.Request) {
 			PreferredPort: req.Stream.PreferredPort,
 			PreferredIP:   req.Stream.PreferredIP,
 			Mode:          req.Stream.LaunchMode,
+			Env:           req.Stream.Env,
+			Args:          req.Stream.Args,
+			Image:         req.Stream.Image,
 		},
 		Host: service.StreamHostConfig{
 			Username: req.Host.Name,
 			AvatarID: req.Host.AvatarID,
-			IP:       util.GetIP(r),
+			IP:       util.GetIP(r, h.trustedProxies),
 		},
-		Subject: subject,
-	})
+		Subject:     subject,
+		MaxDuration: time.Duration(req.Stream.MaxDuration) * time.Second,
+		IdleTimeout: time.Duration(req.Stream.IdleTimeout) * time.Second,
+		Template:    req.Template,
+	}
+	if req.StartAt != nil {
+		cfg.StartAt = *req.StartAt
+	}
+	if req.Stream.Restart != nil {
+		cfg.Launch.Restart = service.StreamRestartConfig{
+			Policy:      req.Stream.Restart.Policy,
+			MaxAttempts: req.Stream.Restart.MaxAttempts,
+		}
+	}
+	if req.Stream.Limits != nil {
+		cfg.Launch.Limits = service.StreamResourceLimits{
+			CPU:    req.Stream.Limits.CPU,
+			Memory: req.Stream.Limits.Memory,
+			PIDs:   req.Stream.Limits.PIDs,
+		}
+	}
+
+	if queue, _ := strconv.ParseBool(r.URL.Query().Get("queue")); queue {
+		h.queueStream(w, r, cfg)
+		return
+	}
+
+	streamInfo, err := h.server.NewStream(r.Context(), cfg)
+	if errors.Is(err, service.ErrInvalidLaunchMode) {
+		middleware.WriteJSONResponse(w, http.StatusBadRequest,
+			middleware.ErrInvalidRequestParam.New([]middleware.RequestParamErrDetails{
+				{
+					Param:  "stream.launch",
+					Errors: []string{err.Error()},
+				},
+			}))
+		return
+	}
+	if errors.Is(err, service.ErrForbiddenStreamEnv) {
+		middleware.WriteJSONResponse(w, http.StatusBadRequest,
+			middleware.ErrInvalidRequestParam.New([]middleware.RequestParamErrDetails{
+				{
+					Param:  "stream.env",
+					Errors: []string{err.Error()},
+				},
+			}))
+		return
+	}
+	if errors.Is(err, service.ErrForbiddenStreamArg) {
+		middleware.WriteJSONResponse(w, http.StatusBadRequest,
+			middleware.ErrInvalidRequestParam.New([]middleware.RequestParamErrDetails{
+				{
+					Param:  "stream.args",
+					Errors: []string{err.Error()},
+				},
+			}))
+		return
+	}
+	if errors.Is(err, service.ErrStreamQuotaExceeded) {
+		middleware.WriteJSONResponse(w, http.StatusTooManyRequests,
+			middleware.ErrQuotaExceeded.New(err.Error()))
+		return
+	}
 	if err != nil {
 		middleware.WriteJSONResponse(w, http.StatusInternalServerError,
 			middleware.ErrCreateStream.New(err.Error()))
@@ -51,6 +118,20 @@ func (h *Router) createStream(w http.ResponseWriter, r *http.Request) {
 	middleware.WriteJSONResponse(w, http.StatusCreated, resp)
 }
 
+func (h *Router) queueStream(w http.ResponseWriter, r *http.Request, cfg service.StreamConfig) {
+	ticket, err := h.server.QueueStream(r.Context(), cfg)
+	if err != nil {
+		middleware.WriteJSONResponse(w, http.StatusServiceUnavailable,
+			middleware.ErrQueueStream.New(err.Error()))
+		return
+	}
+
+	middleware.WriteJSONResponse(w, http.StatusAccepted, models.StreamQueueTicketResponse{
+		Ticket:   ticket.Ticket,
+		Position: ticket.Position,
+	})
+}
+
 func buildStreamOwnerInfoResponse(info *service.StreamOwnerInfo) models.StreamOwnerInfoResponse {
 	resp := models.StreamOwnerInfoResponse{
 		UUID:        info.UUID,
@@ -67,6 +148,7 @@ func buildStreamOwnerInfoResponse(info *service.StreamOwnerInfo) models.StreamOw
 			AvatarID: info.Host.AvatarID,
 			IP:       info.Host.IP,
 		},
+		ScheduledAt: info.ScheduledAt,
 	}
 
 	if info.JoinPolicy == service.JoinPolicyByCode {
@@ -74,10 +156,22 @@ func buildStreamOwnerInfoResponse(info *service.StreamOwnerInfo) models.StreamOw
 	}
 
 	if info.Auth != nil {
-		resp.Auth = &models.AuthorizationInfo{
-			AccessToken: info.Auth.AccessToken,
-			Type:        info.Auth.Type,
-		}
+		auth := buildAuthorizationInfo(info.Auth)
+		resp.Auth = &auth
+	}
+
+	return resp
+}
+
+func buildAuthorizationInfo(auth *service.AuthInfo) models.AuthorizationInfo {
+	resp := models.AuthorizationInfo{
+		AccessToken:  auth.AccessToken,
+		RefreshToken: auth.RefreshToken,
+		Type:         auth.Type,
+	}
+	if !auth.ExpiresAt.IsZero() {
+		expiresAt := auth.ExpiresAt
+		resp.ExpiresAt = &expiresAt
 	}
 
 	return resp
@@ -35,5 +35,8 @@ func buildStreamInfoResponse(info *service.StreamPublicInfo) models.StreamPublic
 		JoinPolicy:  info.JoinPolicy,
 		StartedAt:   info.StartedAt,
 		FinishedAt:  info.FinishedAt,
+		Health:      info.Health,
+		Status:      info.Status,
+		ScheduledAt: info.ScheduledAt,
 	}
 }
@@ -42,5 +42,6 @@ func buildStreamParticipantResponse(participant *service.Participant) models.Par
 		Name:     participant.Name,
 		AvatarID: participant.AvatarID,
 		Status:   participant.Status,
+		Role:     participant.Role,
 	}
 }
@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/code-cord/cc.core.server/handler/middleware"
+	"github.com/code-cord/cc.core.server/handler/models"
+	"github.com/gorilla/mux"
+)
+
+// SSE events of the queued stream.
+const (
+	queueEventPosition = "position"
+	queueEventStream   = "stream"
+	queueEventError    = "error"
+)
+
+func (h *Router) getStreamQueueTicket(w http.ResponseWriter, r *http.Request) {
+	var subject string
+	if v := r.Context().Value(middleware.ServerSubjectKey); v != nil {
+		subject = v.(string)
+	}
+
+	ticketID := mux.Vars(r)["ticket"]
+	updates, err := h.server.WatchStreamQueueTicket(r.Context(), ticketID)
+	if err != nil {
+		middleware.WriteJSONResponse(w, http.StatusNotFound,
+			middleware.ErrQueueTicket.New(err.Error()))
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		middleware.WriteJSONResponse(w, http.StatusBadRequest, middleware.ErrSSEUpgrade.New(nil))
+		return
+	}
+
+	upgraded := false
+	for ticket := range updates {
+		// tickets of other subjects are hidden.
+		if ticket.Subject != subject {
+			middleware.WriteJSONResponse(w, http.StatusNotFound,
+				middleware.ErrQueueTicket.New(nil))
+			return
+		}
+
+		if !upgraded {
+			middleware.UpgradeRequestToSSE(w, "*")
+			w.WriteHeader(http.StatusOK)
+			upgraded = true
+		}
+
+		var err error
+		switch {
+		case ticket.Stream != nil:
+			err = middleware.WriteSSEEvent(w, flusher, queueEventStream,
+				buildStreamOwnerInfoResponse(ticket.Stream))
+		case ticket.Error != "":
+			err = middleware.WriteSSEEvent(w, flusher, queueEventError,
+				middleware.ErrCreateStream.New(ticket.Error))
+		default:
+			err = middleware.WriteSSEEvent(w, flusher, queueEventPosition,
+				models.StreamQueueTicketResponse{
+					Ticket:   ticket.Ticket,
+					Position: ticket.Position,
+				})
+		}
+		if err != nil {
+			return
+		}
+	}
+}
@@ -31,7 +31,7 @@ func (h *Router) joinStream(w http.ResponseWriter, r *http.Request) {
 		r.Context(), streamUUID, req.JoinCode, service.Participant{
 			Name:     req.Name,
 			AvatarID: req.AvatarID,
-			IP:       util.GetIP(r),
+			IP:       util.GetIP(r, h.trustedProxies),
 		})
 	if err != nil {
 		middleware.WriteJSONResponse(w, http.StatusInternalServerError,
@@ -40,8 +40,13 @@ func (h *Router) joinStream(w http.ResponseWriter, r *http.Request) {
 	}
 
 	resp := models.ParticipantJoinResponse{
-		Allowed:     joinDecision.JoinAllowed,
-		AccessToken: joinDecision.AccessToken,
+		Allowed:      joinDecision.JoinAllowed,
+		Status:       joinDecision.Status,
+		AccessToken:  joinDecision.AccessToken,
+		RefreshToken: joinDecision.RefreshToken,
+	}
+	if !joinDecision.ExpiresAt.IsZero() {
+		resp.ExpiresAt = &joinDecision.ExpiresAt
 	}
 
 	middleware.WriteJSONResponse(w, http.StatusOK, resp)
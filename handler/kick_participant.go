@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/code-cord/cc.core.server/handler/middleware"
+	"github.com/code-cord/cc.core.server/service"
+	"github.com/gorilla/mux"
+)
+
+func (h *Router) kickParticipant(w http.ResponseWriter, r *http.Request) {
+	ctxData := r.Context().Value(middleware.ParticipantKey)
+	if ctxData == nil {
+		middleware.WriteJSONResponse(w, http.StatusUnauthorized,
+			middleware.ErrAuth.New("invalid context data"))
+		return
+	}
+	caller := ctxData.(middleware.ParticipantCtxData)
+
+	vars := mux.Vars(r)
+	streamUUID := vars["uuid"]
+	participantUUID := vars["participantUUID"]
+
+	err := h.server.KickParticipant(r.Context(), streamUUID, caller.UUID, participantUUID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrInsufficientRole) {
+			status = http.StatusForbidden
+		}
+
+		middleware.WriteJSONResponse(w, status, middleware.ErrKickParticipant.New(err.Error()))
+		return
+	}
+
+	middleware.WriteJSONResponse(w, http.StatusOK, nil)
+}
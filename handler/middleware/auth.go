@@ -3,9 +3,11 @@ package middleware
 import (
 	"context"
 	"crypto/rsa"
+	"crypto/subtle"
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/code-cord/cc.core.server/service"
 	"github.com/golang-jwt/jwt"
@@ -13,9 +15,11 @@ import (
 )
 
 const (
-	serverAuthTokenHeader = "X-CODE-CORD-AUTH"
-	authTokenHeader       = "Authorization"
-	bearerPrefix          = "Bearer "
+	serverAuthTokenHeader  = "X-CODE-CORD-AUTH"
+	authTokenHeader        = "Authorization"
+	bearerPrefix           = "Bearer "
+	streamTokenTypeClaim   = "typ"
+	streamTokenTypeRefresh = "refresh"
 )
 
 // Server context key.
@@ -32,6 +36,7 @@ type ParticipantCtxData struct {
 	UUID       string
 	StreamUUID string
 	IsHost     bool
+	Role       service.ParticipantRole
 }
 
 // ServerAuthMiddleware represents middleware func to check access to the server-side operations.
@@ -76,6 +81,19 @@ func ServerAuthMiddleware(publicKey *rsa.PublicKey) func(http.Handler) http.Hand
 // StreamAuthMiddleware represents middleware func to check access to the stream operations.
 func StreamAuthMiddleware(
 	server service.Server, hostSpecific bool) func(http.Handler) http.Handler {
+	if hostSpecific {
+		return StreamRoleAuthMiddleware(server, service.ParticipantRoleHost)
+	}
+
+	return StreamRoleAuthMiddleware(server)
+}
+
+// StreamRoleAuthMiddleware represents middleware func to check access to the stream operations
+// of the participants with one of the roles.
+//
+// If no roles are provided, participants with any role have access.
+func StreamRoleAuthMiddleware(
+	server service.Server, roles ...service.ParticipantRole) func(http.Handler) http.Handler {
 	return func(h http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			authToken := r.Header.Get(authTokenHeader)
@@ -107,13 +125,29 @@ func StreamAuthMiddleware(
 				return
 			}
 
+			// stream tokens must be short-lived access tokens.
+			if !claims.VerifyExpiresAt(time.Now().Unix(), true) {
+				WriteJSONResponse(w, http.StatusUnauthorized,
+					ErrAuth.New("token has no expiration time"))
+				return
+			}
+			if claims[streamTokenTypeClaim] == streamTokenTypeRefresh {
+				WriteJSONResponse(w, http.StatusUnauthorized,
+					ErrAuth.New("refresh token can't be used to access the stream"))
+				return
+			}
+
+			jti, _ := claims["jti"].(string)
+			revoked, err := server.StreamTokenRevoked(r.Context(), streamUUID, jti)
+			if err != nil || revoked {
+				WriteJSONResponse(w, http.StatusUnauthorized, ErrAuth.New("token is revoked"))
+				return
+			}
+
 			participant := ParticipantCtxData{
 				UUID:       claims["UUID"].(string),
 				StreamUUID: claims["streamUUID"].(string),
 			}
-			if isHost, ok := claims["host"]; ok {
-				participant.IsHost = isHost.(bool)
-			}
 
 			if streamUUID != participant.StreamUUID {
 				WriteJSONResponse(w, http.StatusForbidden,
@@ -121,9 +155,21 @@ func StreamAuthMiddleware(
 				return
 			}
 
-			if hostSpecific && !participant.IsHost {
-				WriteJSONResponse(w, http.StatusUnauthorized,
-					ErrAuth.New("only host of the stream has access to this endpoint"))
+			// kicked and banned participants lose access to the stream. The role is
+			// taken from the storage since it may have been changed after the token was
+			// issued, e.g. the host role may have been transferred.
+			p, err := server.Participant(r.Context(), streamUUID, participant.UUID)
+			if err != nil || p.Status != service.ParticipantStatusActive {
+				WriteJSONResponse(w, http.StatusForbidden,
+					ErrAuth.New("participant has no access to the stream"))
+				return
+			}
+			participant.Role = p.Role
+			participant.IsHost = p.Role == service.ParticipantRoleHost
+
+			if !hasParticipantRole(participant.Role, roles) {
+				WriteJSONResponse(w, http.StatusForbidden,
+					ErrAuth.New(fmt.Sprintf("%s has no access to this endpoint", participant.Role)))
 				return
 			}
 
@@ -134,3 +180,41 @@ func StreamAuthMiddleware(
 		})
 	}
 }
+
+func hasParticipantRole(role service.ParticipantRole, roles []service.ParticipantRole) bool {
+	if len(roles) == 0 {
+		return true
+	}
+
+	for i := range roles {
+		if roles[i] == role {
+			return true
+		}
+	}
+
+	return false
+}
+
+// WorkerAuthMiddleware represents middleware func to check access to the worker operations.
+//
+// Requests must carry the shared worker token as a bearer token.
+func WorkerAuthMiddleware(workerToken string) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authHeader := r.Header.Get(authTokenHeader)
+			if !strings.HasPrefix(authHeader, bearerPrefix) {
+				WriteJSONResponse(w, http.StatusUnauthorized,
+					ErrAuth.New("bearer token is missing"))
+				return
+			}
+
+			token := strings.TrimPrefix(authHeader, bearerPrefix)
+			if subtle.ConstantTimeCompare([]byte(token), []byte(workerToken)) != 1 {
+				WriteJSONResponse(w, http.StatusUnauthorized, ErrAuth.New("invalid token"))
+				return
+			}
+
+			h.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/code-cord/cc.core.server/service"
+	"github.com/golang-jwt/jwt"
+	"github.com/gorilla/mux"
+)
+
+const testStreamUUID = "2f1d7c3e-5a8b-4c6d-9e0f-1a2b3c4d5e6f"
+
+// testServer represents server knowing the stream keys, participants and revoked tokens only.
+type testServer struct {
+	service.Server
+	key          *rsa.PrivateKey
+	participants map[string]service.Participant
+	revoked      map[string]bool
+}
+
+func (s *testServer) StreamKey(ctx context.Context, streamUUID string) (*rsa.PublicKey, error) {
+	if streamUUID != testStreamUUID {
+		return nil, os.ErrNotExist
+	}
+
+	return &s.key.PublicKey, nil
+}
+
+func (s *testServer) StreamTokenRevoked(ctx context.Context, streamUUID, jti string) (bool, error) {
+	return s.revoked[jti], nil
+}
+
+func (s *testServer) Participant(ctx context.Context, streamUUID, participantUUID string) (
+	*service.Participant, error) {
+	p, ok := s.participants[participantUUID]
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+
+	return &p, nil
+}
+
+func TestStreamRoleAuthMiddleware(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("could not generate key: %v", err)
+	}
+
+	server := testServer{
+		key: key,
+		participants: map[string]service.Participant{
+			"host":    {UUID: "host", Status: service.ParticipantStatusActive, Role: service.ParticipantRoleHost},
+			"co-host": {UUID: "co-host", Status: service.ParticipantStatusActive, Role: service.ParticipantRoleCoHost},
+			"editor":  {UUID: "editor", Status: service.ParticipantStatusActive, Role: service.ParticipantRoleEditor},
+			"banned":  {UUID: "banned", Status: service.ParticipantStatusBlocked, Role: service.ParticipantRoleCoHost},
+		},
+		revoked: map[string]bool{
+			"revoked-jti": true,
+		},
+	}
+
+	router := mux.NewRouter()
+	router.Use(StreamRoleAuthMiddleware(&server,
+		service.ParticipantRoleHost, service.ParticipantRoleCoHost))
+	router.Path("/stream/{uuid}").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		participant := r.Context().Value(ParticipantKey).(ParticipantCtxData)
+		if participant.IsHost != (participant.Role == service.ParticipantRoleHost) {
+			t.Errorf("unexpected host flag of %s", participant.Role)
+		}
+
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name        string
+		participant string
+		streamUUID  string
+		claims      jwt.MapClaims
+		want        int
+	}{
+		{name: "host", participant: "host", want: http.StatusOK},
+		{name: "co-host", participant: "co-host", want: http.StatusOK},
+		{
+			name:        "role is taken from the server",
+			participant: "co-host",
+			claims:      jwt.MapClaims{"role": service.ParticipantRoleViewer},
+			want:        http.StatusOK,
+		},
+		{name: "editor", participant: "editor", want: http.StatusForbidden},
+		{name: "banned participant", participant: "banned", want: http.StatusForbidden},
+		{name: "unknown participant", participant: "unknown", want: http.StatusForbidden},
+		{
+			name:        "revoked token",
+			participant: "host",
+			claims:      jwt.MapClaims{"jti": "revoked-jti"},
+			want:        http.StatusUnauthorized,
+		},
+		{
+			name:        "refresh token",
+			participant: "host",
+			claims:      jwt.MapClaims{streamTokenTypeClaim: streamTokenTypeRefresh},
+			want:        http.StatusUnauthorized,
+		},
+		{
+			name:        "expired token",
+			participant: "host",
+			claims:      jwt.MapClaims{"exp": time.Now().Add(-time.Minute).Unix()},
+			want:        http.StatusUnauthorized,
+		},
+		{
+			name:        "token of another stream",
+			participant: "host",
+			claims:      jwt.MapClaims{"streamUUID": "another-stream"},
+			want:        http.StatusForbidden,
+		},
+		{
+			name:        "unknown stream",
+			participant: "host",
+			streamUUID:  "unknown-stream",
+			want:        http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := jwt.MapClaims{
+				"streamUUID":         testStreamUUID,
+				"UUID":               tt.participant,
+				"exp":                time.Now().Add(time.Minute).Unix(),
+				"jti":                "jti",
+				streamTokenTypeClaim: "access",
+			}
+			for k, v := range tt.claims {
+				claims[k] = v
+			}
+			token, err := jwt.NewWithClaims(jwt.SigningMethodRS256, claims).SignedString(key)
+			if err != nil {
+				t.Fatalf("could not sign token: %v", err)
+			}
+
+			streamUUID := tt.streamUUID
+			if streamUUID == "" {
+				streamUUID = testStreamUUID
+			}
+			r := httptest.NewRequest(http.MethodGet, "/stream/"+streamUUID, nil)
+			r.Header.Set(authTokenHeader, bearerPrefix+token)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, r)
+			if w.Code != tt.want {
+				t.Errorf("unexpected status: got %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
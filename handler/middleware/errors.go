@@ -21,6 +21,16 @@ const (
 	errCodeStreamList        = 2005
 	errCodeBackupStorage     = 2006
 	errCodeUpdateParticipant = 2007
+	errCodeStreamLogs        = 2008
+	errCodeCreateTemplate    = 2009
+	errCodeTemplateList      = 2010
+	errCodeFetchTemplate     = 2011
+	errCodeUpdateTemplate    = 2012
+	errCodeDeleteTemplate    = 2013
+	errCodeQuotaExceeded     = 2014
+	errCodeQuotaUsage        = 2015
+	errCodeQueueStream       = 2016
+	errCodeQueueTicket       = 2017
 
 	// stream errors 3xxx.
 	errCodeJoinStream              = 3000
@@ -28,6 +38,24 @@ const (
 	errCodeDecideParticipantJoin   = 3002
 	errCodeGenerateStreamToken     = 3003
 	errCodeStreamInfo              = 3004
+	errCodePauseStream             = 3005
+	errCodeResumeStream            = 3006
+	errCodeStreamPaused            = 3007
+	errCodeKickParticipant         = 3008
+	errCodeBanParticipant          = 3009
+	errCodeRefreshStreamToken      = 3010
+	errCodeChangeParticipantRole   = 3011
+	errCodeTransferStreamHost      = 3012
+
+	// worker errors 4xxx.
+	errCodeWorkerStartStream  = 4000
+	errCodeWorkerStream       = 4001
+	errCodeWorkerStopStream   = 4002
+	errCodeWorkerPauseStream  = 4003
+	errCodeWorkerResumeStream = 4004
+	errCodeWorkerStreamLogs   = 4005
+	errCodeWorkerWaitStream   = 4006
+	errCodeWorkerFull         = 4007
 )
 
 // Custom error (aka unexpected error).
@@ -92,6 +120,46 @@ var (
 		Code:    errCodeUpdateParticipant,
 		Message: "could not update participant info",
 	}
+	ErrStreamLogs = Error{
+		Code:    errCodeStreamLogs,
+		Message: "could not fetch stream logs",
+	}
+	ErrCreateTemplate = Error{
+		Code:    errCodeCreateTemplate,
+		Message: "could not create stream template",
+	}
+	ErrTemplateList = Error{
+		Code:    errCodeTemplateList,
+		Message: "could not fetch stream template list",
+	}
+	ErrFetchTemplate = Error{
+		Code:    errCodeFetchTemplate,
+		Message: "could not fetch stream template",
+	}
+	ErrUpdateTemplate = Error{
+		Code:    errCodeUpdateTemplate,
+		Message: "could not update stream template",
+	}
+	ErrDeleteTemplate = Error{
+		Code:    errCodeDeleteTemplate,
+		Message: "could not delete stream template",
+	}
+	ErrQuotaExceeded = Error{
+		Code:    errCodeQuotaExceeded,
+		Message: "stream quota exceeded",
+	}
+	ErrQuotaUsage = Error{
+		Code:    errCodeQuotaUsage,
+		Message: "could not fetch stream quota usage",
+	}
+	ErrQueueStream = Error{
+		Code:    errCodeQueueStream,
+		Message: "could not queue stream",
+	}
+	ErrQueueTicket = Error{
+		Code:    errCodeQueueTicket,
+		Message: "could not find stream queue ticket",
+	}
 )
 
 // Stream error.
@@ -116,6 +184,74 @@ var (
 		Code:    errCodeStreamInfo,
 		Message: "could not get stream info",
 	}
+	ErrPauseStream = Error{
+		Code:    errCodePauseStream,
+		Message: "could not pause stream",
+	}
+	ErrResumeStream = Error{
+		Code:    errCodeResumeStream,
+		Message: "could not resume stream",
+	}
+	ErrStreamPaused = Error{
+		Code:    errCodeStreamPaused,
+		Message: "stream is paused",
+	}
+	ErrKickParticipant = Error{
+		Code:    errCodeKickParticipant,
+		Message: "could not kick participant",
+	}
+	ErrBanParticipant = Error{
+		Code:    errCodeBanParticipant,
+		Message: "could not ban participant",
+	}
+	ErrRefreshStreamToken = Error{
+		Code:    errCodeRefreshStreamToken,
+		Message: "could not refresh access token",
+	}
+	ErrChangeParticipantRole = Error{
+		Code:    errCodeChangeParticipantRole,
+		Message: "could not change participant role",
+	}
+	ErrTransferStreamHost = Error{
+		Code:    errCodeTransferStreamHost,
+		Message: "could not transfer stream host",
+	}
+)
+
+// Worker error.
+var (
+	ErrWorkerStartStream = Error{
+		Code:    errCodeWorkerStartStream,
+		Message: "could not start stream on worker",
+	}
+	ErrWorkerStream = Error{
+		Code:    errCodeWorkerStream,
+		Message: "could not find stream on worker",
+	}
+	ErrWorkerStopStream = Error{
+		Code:    errCodeWorkerStopStream,
+		Message: "could not stop stream on worker",
+	}
+	ErrWorkerPauseStream = Error{
+		Code:    errCodeWorkerPauseStream,
+		Message: "could not pause stream on worker",
+	}
+	ErrWorkerResumeStream = Error{
+		Code:    errCodeWorkerResumeStream,
+		Message: "could not resume stream on worker",
+	}
+	ErrWorkerStreamLogs = Error{
+		Code:    errCodeWorkerStreamLogs,
+		Message: "could not fetch stream logs on worker",
+	}
+	ErrWorkerWaitStream = Error{
+		Code:    errCodeWorkerWaitStream,
+		Message: "could not wait for stream on worker",
+	}
+	ErrWorkerFull = Error{
+		Code:    errCodeWorkerFull,
+		Message: "worker is at full capacity",
+	}
 )
 
 // Error represents generic model for error.
@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 )
 
@@ -14,3 +15,18 @@ func WriteJSONResponse(w http.ResponseWriter, statusCode int, body interface{})
 		json.NewEncoder(w).Encode(body)
 	}
 }
+
+// WriteSSEEvent writes JSON encoded server-sent event and flushes it.
+func WriteSSEEvent(w http.ResponseWriter, flusher http.Flusher, event string, body interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("could not encode event data: %v", err)
+	}
+
+	if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data); err != nil {
+		return fmt.Errorf("could not write event: %v", err)
+	}
+	flusher.Flush()
+
+	return nil
+}
@@ -3,6 +3,7 @@ package models
 import (
 	"fmt"
 	"net/url"
+	"regexp"
 	"strconv"
 	"time"
 
@@ -64,6 +65,19 @@ type StreamInfoResponse struct {
 	Status      service.StreamStatus     `json:"status"`
 	Join        StreamJoinConfigResponse `json:"join"`
 	Host        HostOwnerInfo            `json:"host"`
+	Restarts    int                      `json:"restarts"`
+	Health      service.StreamHealth     `json:"health,omitempty"`
+	Limits      StreamLimitsResponse     `json:"limits"`
+	ScheduledAt *time.Time               `json:"scheduledAt,omitempty"`
+	Worker      string                   `json:"worker,omitempty"`
+	Error       string                   `json:"error,omitempty"`
+}
+
+// StreamLimitsResponse represents stream resource limits response model.
+type StreamLimitsResponse struct {
+	CPU    float64 `json:"cpu,omitempty"`
+	Memory int64   `json:"memory,omitempty"`
+	PIDs   int64   `json:"pids,omitempty"`
 }
 
 // StreamJoinConfigResponse represents stream join config response model.
@@ -85,10 +99,7 @@ func (req *GenerateServerTokenRequest) Validate() error {
 // Validate validates request model.
 func (req *StreamListRequest) Validate() error {
 	modeValidationRules := []validation.Rule{
-		validation.In(
-			service.StreamLaunchModeDockerContainer,
-			service.StreamLaunchModeStandaloneApp,
-		),
+		validation.Match(service.LaunchModeNameRegexp),
 	}
 	for i := range req.LaunchModes {
 		err := validation.Errors{
@@ -105,6 +116,8 @@ func (req *StreamListRequest) Validate() error {
 		validation.In(
 			service.StreamStatusFinished,
 			service.StreamStatusRunning,
+			service.StreamStatusPaused,
+			service.StreamStatusScheduled,
 		),
 	}
 	for i := range req.Statuses {
@@ -191,3 +204,107 @@ func (req *StreamListRequest) Build(values url.Values) error {
 
 	return nil
 }
+
+// StreamLogsRequest represents stream logs request model.
+type StreamLogsRequest struct {
+	Follow bool
+}
+
+// Build builds request model from URL.
+func (req *StreamLogsRequest) Build(values url.Values) error {
+	if follow := values.Get("follow"); follow != "" {
+		isFollow, err := strconv.ParseBool(follow)
+		if err != nil {
+			return fmt.Errorf("could not parse follow param: %v", err)
+		}
+		req.Follow = isFollow
+	}
+
+	return nil
+}
+
+// StreamTemplateRequest represents stream template request model.
+//
+// Stream.MaxDuration and Stream.IdleTimeout are set in seconds.
+type StreamTemplateRequest struct {
+	Name        string              `json:"name"`
+	Description string              `json:"description"`
+	Join        JoinPolicyRequest   `json:"join"`
+	Stream      StreamConfigRequest `json:"stream"`
+}
+
+// StreamTemplateResponse represents stream template response model.
+type StreamTemplateResponse struct {
+	Name        string                       `json:"name"`
+	Description string                       `json:"description,omitempty"`
+	Join        StreamTemplateJoinResponse   `json:"join"`
+	Stream      StreamTemplateConfigResponse `json:"stream"`
+}
+
+// StreamTemplateJoinResponse represents stream template join policy response model.
+type StreamTemplateJoinResponse struct {
+	Policy service.JoinPolicy `json:"policy,omitempty"`
+	Code   string             `json:"code,omitempty"`
+}
+
+// StreamTemplateConfigResponse represents stream template configuration response model.
+type StreamTemplateConfigResponse struct {
+	PreferredPort int                      `json:"port,omitempty"`
+	PreferredIP   string                   `json:"ip,omitempty"`
+	LaunchMode    service.StreamLaunchMode `json:"launch,omitempty"`
+	Restart       *StreamRestartResponse   `json:"restart,omitempty"`
+	Limits        StreamLimitsResponse     `json:"limits"`
+	MaxDuration   int                      `json:"maxDuration,omitempty"`
+	IdleTimeout   int                      `json:"idleTimeout,omitempty"`
+	Env           map[string]string        `json:"env,omitempty"`
+	Args          []string                 `json:"args,omitempty"`
+	Image         string                   `json:"image,omitempty"`
+}
+
+// StreamRestartResponse represents stream restart policy response model.
+type StreamRestartResponse struct {
+	Policy      service.StreamRestartPolicy `json:"policy"`
+	MaxAttempts int                         `json:"maxAttempts,omitempty"`
+}
+
+// Validate validates request model.
+func (req *StreamTemplateRequest) Validate() error {
+	errs := validation.Errors{
+		"name": validation.Validate(req.Name,
+			validation.Required,
+			validation.Match(regexp.MustCompile("^[a-zA-Z0-9_-]{3,32}$")),
+		),
+		"description": validation.Validate(req.Description,
+			validation.Length(0, 96),
+		),
+	}
+
+	validateJoinPolicyRequest(&req.Join, false, errs)
+	validateStreamConfigRequest(&req.Stream, errs)
+
+	return errs.Filter()
+}
+
+// QuotaUsageResponse represents stream quotas usage response model.
+//
+// Limits.MaxSubjectStreamTime, Limits.Period and Subjects.StreamTime are set in seconds.
+type QuotaUsageResponse struct {
+	Limits   StreamQuotaResponse         `json:"limits"`
+	Running  int                         `json:"running"`
+	Subjects []SubjectQuotaUsageResponse `json:"subjects"`
+}
+
+// StreamQuotaResponse represents stream quotas response model.
+type StreamQuotaResponse struct {
+	MaxStreams           int `json:"maxStreams,omitempty"`
+	MaxSubjectStreams    int `json:"maxSubjectStreams,omitempty"`
+	MaxSubjectStreamTime int `json:"maxSubjectStreamTime,omitempty"`
+	Period               int `json:"period"`
+}
+
+// SubjectQuotaUsageResponse represents stream quotas usage of the subject response model.
+type SubjectQuotaUsageResponse struct {
+	Subject    string `json:"subject"`
+	Running    int    `json:"running"`
+	StreamTime int    `json:"streamTime"`
+}
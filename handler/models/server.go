@@ -1,11 +1,14 @@
 package models
 
+import "github.com/code-cord/cc.core.server/service"
+
 // ServerInfoResponse represents server info response model.
 type ServerInfoResponse struct {
-	Name           string                 `json:"name"`
-	Description    string                 `json:"description"`
-	Version        string                 `json:"version"`
-	AdditionalInfo map[string]interface{} `json:"info,omitempty"`
+	Name           string                     `json:"name"`
+	Description    string                     `json:"description"`
+	Version        string                     `json:"version"`
+	AdditionalInfo map[string]interface{}     `json:"info,omitempty"`
+	LaunchModes    []service.StreamLaunchMode `json:"launchModes"`
 }
 
 // PongResponse represents pong response model.
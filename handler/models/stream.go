@@ -9,6 +9,16 @@ import (
 	"github.com/go-ozzo/ozzo-validation/v4/is"
 )
 
+const (
+	maxStreamEnvCount = 64
+	maxStreamArgCount = 64
+)
+
+var (
+	envNameRegexp     = regexp.MustCompile("^[a-zA-Z_][a-zA-Z0-9_]*$")
+	dockerImageRegexp = regexp.MustCompile("^[a-z0-9][a-z0-9._/:@-]*$")
+)
+
 // CreateStreamRequest represents create stream request model.
 type CreateStreamRequest struct {
 	Name        string                `json:"name"`
@@ -16,6 +26,14 @@ type CreateStreamRequest struct {
 	Join        JoinPolicyRequest     `json:"join"`
 	Stream      StreamConfigRequest   `json:"stream"`
 	Host        StreamHostInfoRequest `json:"host"`
+	StartAt     *time.Time            `json:"startAt,omitempty"`
+	Template    string                `json:"template,omitempty"`
+}
+
+// StreamQueueTicketResponse represents queued stream response model.
+type StreamQueueTicketResponse struct {
+	Ticket   string `json:"ticket"`
+	Position int    `json:"position"`
 }
 
 // JoinPolicyRequest represents join policy request model.
@@ -25,10 +43,32 @@ type JoinPolicyRequest struct {
 }
 
 // StreamConfigRequest represents stream configuration request model.
+//
+// MaxDuration and IdleTimeout are set in seconds.
 type StreamConfigRequest struct {
 	PreferredPort int                      `json:"port"`
 	PreferredIP   string                   `json:"ip"`
 	LaunchMode    service.StreamLaunchMode `json:"launch"`
+	Restart       *StreamRestartRequest    `json:"restart,omitempty"`
+	Limits        *StreamLimitsRequest     `json:"limits,omitempty"`
+	MaxDuration   int                      `json:"maxDuration,omitempty"`
+	IdleTimeout   int                      `json:"idleTimeout,omitempty"`
+	Env           map[string]string        `json:"env,omitempty"`
+	Args          []string                 `json:"args,omitempty"`
+	Image         string                   `json:"image,omitempty"`
+}
+
+// StreamRestartRequest represents stream restart policy request model.
+type StreamRestartRequest struct {
+	Policy      service.StreamRestartPolicy `json:"policy"`
+	MaxAttempts int                         `json:"maxAttempts,omitempty"`
+}
+
+// StreamLimitsRequest represents stream resource limits request model.
+type StreamLimitsRequest struct {
+	CPU    float64 `json:"cpu,omitempty"`
+	Memory int64   `json:"memory,omitempty"`
+	PIDs   int64   `json:"pids,omitempty"`
 }
 
 // StreamHostInfoRequest represents stream host info request model.
@@ -50,6 +90,7 @@ type StreamOwnerInfoResponse struct {
 	LaunchMode  service.StreamLaunchMode `json:"launchMode"`
 	HostInfo    HostOwnerInfo            `json:"host"`
 	Auth        *AuthorizationInfo       `json:"auth,omitempty"`
+	ScheduledAt *time.Time               `json:"scheduledAt,omitempty"`
 }
 
 // HostOwnerInfo represents host owner info response.
@@ -62,18 +103,28 @@ type HostOwnerInfo struct {
 
 // AuthorizationInfo represents authorization info model.
 type AuthorizationInfo struct {
-	AccessToken string `json:"accessToken"`
-	Type        string `json:"type"`
+	AccessToken  string     `json:"accessToken"`
+	RefreshToken string     `json:"refreshToken,omitempty"`
+	ExpiresAt    *time.Time `json:"expiresAt,omitempty"`
+	Type         string     `json:"type"`
+}
+
+// RefreshTokenRequest represents refresh stream token request model.
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refreshToken"`
 }
 
 // StreamPublicInfoResponse represents stream public info response model.
 type StreamPublicInfoResponse struct {
-	UUID        string             `json:"streamUUID"`
-	Name        string             `json:"name"`
-	Description string             `json:"description"`
-	JoinPolicy  service.JoinPolicy `json:"joinPolicy"`
-	StartedAt   time.Time          `json:"startedAt"`
-	FinishedAt  *time.Time         `json:"finishedAt,omitempty"`
+	UUID        string               `json:"streamUUID"`
+	Name        string               `json:"name"`
+	Description string               `json:"description"`
+	JoinPolicy  service.JoinPolicy   `json:"joinPolicy"`
+	StartedAt   time.Time            `json:"startedAt"`
+	FinishedAt  *time.Time           `json:"finishedAt,omitempty"`
+	Health      service.StreamHealth `json:"health,omitempty"`
+	Status      service.StreamStatus `json:"status"`
+	ScheduledAt *time.Time           `json:"scheduledAt,omitempty"`
 }
 
 // ParticipantJoinRequest represents participant join request model.
@@ -85,8 +136,11 @@ type ParticipantJoinRequest struct {
 
 // ParticipantJoinResponse represents participant join response model.
 type ParticipantJoinResponse struct {
-	Allowed     bool   `json:"allowed"`
-	AccessToken string `json:"accessToken,omitempty"`
+	Allowed      bool               `json:"allowed"`
+	Status       service.JoinStatus `json:"status"`
+	AccessToken  string             `json:"accessToken,omitempty"`
+	RefreshToken string             `json:"refreshToken,omitempty"`
+	ExpiresAt    *time.Time         `json:"expiresAt,omitempty"`
 }
 
 // ParticipantResponse represents participant response model.
@@ -95,6 +149,23 @@ type ParticipantResponse struct {
 	Name     string                    `json:"name"`
 	AvatarID string                    `json:"avatarId"`
 	Status   service.ParticipantStatus `json:"status"`
+	Role     service.ParticipantRole   `json:"role"`
+}
+
+// TransferHostRequest represents transfer stream host request model.
+type TransferHostRequest struct {
+	ParticipantUUID string `json:"participantUUID"`
+}
+
+// TransferHostResponse represents transfer stream host response model.
+type TransferHostResponse struct {
+	HostInfo HostOwnerInfo     `json:"host"`
+	Auth     AuthorizationInfo `json:"auth"`
+}
+
+// ChangeParticipantRoleRequest represents change participant role request model.
+type ChangeParticipantRoleRequest struct {
+	Role service.ParticipantRole `json:"role"`
 }
 
 // PathcStreamRequest represents patch stream request model.
@@ -121,49 +192,155 @@ func (req *CreateStreamRequest) Validate() error {
 		"description": validation.Validate(req.Description,
 			validation.Length(0, 96),
 		),
-		"join.policy": validation.Validate(req.Join.Policy,
-			validation.Required,
-			validation.In(
-				service.JoinPolicyAuto,
-				service.JoinPolicyByCode,
-				service.JoinPolicyHostResolve,
-			),
-		),
 		"host.username": validation.Validate(req.Host.Name,
 			validation.Required,
 			validation.Length(5, 32),
 		),
 	}
 
-	if req.Join.Policy == service.JoinPolicyByCode {
-		errs["join.code"] = validation.Validate(req.Join.Code,
+	if req.StartAt != nil {
+		errs["startAt"] = validation.Validate(*req.StartAt,
+			validation.Min(time.Now()),
+		)
+	}
+
+	// join policy may be provided by the template.
+	validateJoinPolicyRequest(&req.Join, req.Template == "", errs)
+	validateStreamConfigRequest(&req.Stream, errs)
+
+	return errs.Filter()
+}
+
+func validateJoinPolicyRequest(req *JoinPolicyRequest, required bool, errs validation.Errors) {
+	policyRules := []validation.Rule{
+		validation.In(
+			service.JoinPolicyAuto,
+			service.JoinPolicyByCode,
+			service.JoinPolicyHostResolve,
+		),
+	}
+	if required {
+		policyRules = append(policyRules, validation.Required)
+	}
+	errs["join.policy"] = validation.Validate(req.Policy, policyRules...)
+
+	if req.Policy == service.JoinPolicyByCode {
+		errs["join.code"] = validation.Validate(req.Code,
 			validation.Required,
 			validation.Match(regexp.MustCompile("^[0-9]{6}$")),
 		)
 	}
+}
 
-	if req.Stream.LaunchMode != "" {
-		errs["stream.launch"] = validation.Validate(req.Stream.LaunchMode,
-			validation.In(
-				service.StreamLaunchModeDockerContainer,
-				service.StreamLaunchModeStandaloneApp,
-			),
+func validateStreamConfigRequest(req *StreamConfigRequest, errs validation.Errors) {
+	if req.LaunchMode != "" {
+		errs["stream.launch"] = validation.Validate(req.LaunchMode,
+			validation.Match(service.LaunchModeNameRegexp),
 		)
 	}
 
-	if req.Stream.PreferredIP != "" {
-		errs["stream.ip"] = validation.Validate(req.Stream.PreferredIP,
+	if req.PreferredIP != "" {
+		errs["stream.ip"] = validation.Validate(req.PreferredIP,
 			is.IP,
 		)
 	}
 
-	if req.Stream.PreferredPort != 0 {
-		errs["stream.port"] = validation.Validate(req.Stream.PreferredPort,
+	if req.PreferredPort != 0 {
+		errs["stream.port"] = validation.Validate(req.PreferredPort,
 			validation.Min(0),
 		)
 	}
 
-	return errs.Filter()
+	if req.Restart != nil {
+		errs["stream.restart.policy"] = validation.Validate(req.Restart.Policy,
+			validation.Required,
+			validation.In(
+				service.StreamRestartPolicyNever,
+				service.StreamRestartPolicyOnFailure,
+			),
+		)
+		errs["stream.restart.maxAttempts"] = validation.Validate(req.Restart.MaxAttempts,
+			validation.Min(0),
+		)
+	}
+
+	errs["stream.maxDuration"] = validation.Validate(req.MaxDuration,
+		validation.Min(0),
+	)
+	errs["stream.idleTimeout"] = validation.Validate(req.IdleTimeout,
+		validation.Min(0),
+	)
+
+	if req.Limits != nil {
+		errs["stream.limits.cpu"] = validation.Validate(req.Limits.CPU,
+			validation.Min(0.0),
+		)
+		errs["stream.limits.memory"] = validation.Validate(req.Limits.Memory,
+			validation.Min(0),
+		)
+		errs["stream.limits.pids"] = validation.Validate(req.Limits.PIDs,
+			validation.Min(0),
+		)
+	}
+
+	validateStreamCommandRequest(req.Env, req.Args, "stream.", errs)
+
+	if req.Image != "" {
+		errs["stream.image"] = validation.Validate(req.Image,
+			validation.Length(1, 255),
+			validation.Match(dockerImageRegexp),
+		)
+	}
+}
+
+// validateStreamCommandRequest validates environment variables and arguments
+// of the stream instance.
+func validateStreamCommandRequest(
+	env map[string]string, args []string, prefix string, errs validation.Errors) {
+	envNames := make([]string, 0, len(env))
+	for name := range env {
+		envNames = append(envNames, name)
+	}
+	errs[prefix+"env"] = validation.Validate(envNames,
+		validation.Length(0, maxStreamEnvCount),
+		validation.Each(validation.Match(envNameRegexp)),
+	)
+	errs[prefix+"args"] = validation.Validate(args,
+		validation.Length(0, maxStreamArgCount),
+		validation.Each(validation.Required, validation.Length(1, 1024)),
+	)
+}
+
+// Validate validates request model.
+func (req *RefreshTokenRequest) Validate() error {
+	return validation.Errors{
+		"refreshToken": validation.Validate(req.RefreshToken,
+			validation.Required,
+		),
+	}.Filter()
+}
+
+// Validate validates request model.
+func (req *TransferHostRequest) Validate() error {
+	return validation.Errors{
+		"participantUUID": validation.Validate(req.ParticipantUUID,
+			validation.Required,
+		),
+	}.Filter()
+}
+
+// Validate validates request model.
+func (req *ChangeParticipantRoleRequest) Validate() error {
+	return validation.Errors{
+		"role": validation.Validate(req.Role,
+			validation.Required,
+			validation.In(
+				service.ParticipantRoleCoHost,
+				service.ParticipantRoleEditor,
+				service.ParticipantRoleViewer,
+			),
+		),
+	}.Filter()
 }
 
 // Validate validates request model.
@@ -0,0 +1,81 @@
+package models
+
+import (
+	"github.com/code-cord/cc.core.server/service"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+)
+
+// WorkerInfoResponse represents worker info response model.
+type WorkerInfoResponse struct {
+	Name       string                   `json:"name"`
+	Version    string                   `json:"version"`
+	LaunchMode service.StreamLaunchMode `json:"launchMode"`
+	Capacity   int                      `json:"capacity"`
+	Streams    int                      `json:"streams"`
+}
+
+// WorkerStartStreamRequest represents start stream on worker request model.
+type WorkerStartStreamRequest struct {
+	UUID          string               `json:"uuid"`
+	PreferredPort int                  `json:"port,omitempty"`
+	PreferredIP   string               `json:"ip,omitempty"`
+	Limits        *StreamLimitsRequest `json:"limits,omitempty"`
+	Env           map[string]string    `json:"env,omitempty"`
+	Args          []string             `json:"args,omitempty"`
+	Image         string               `json:"image,omitempty"`
+}
+
+// WorkerStreamResponse represents stream run by worker response model.
+type WorkerStreamResponse struct {
+	UUID string `json:"uuid"`
+	IP   string `json:"ip"`
+	Port int    `json:"port"`
+}
+
+// WorkerStreamExitResponse represents exit of the stream run by worker response model.
+type WorkerStreamExitResponse struct {
+	Error string `json:"error,omitempty"`
+}
+
+// Validate validates request model.
+func (req *WorkerStartStreamRequest) Validate() error {
+	errs := validation.Errors{
+		"uuid": validation.Validate(req.UUID,
+			validation.Required,
+			is.UUID,
+		),
+		"port": validation.Validate(req.PreferredPort,
+			validation.Min(0),
+		),
+	}
+
+	if req.PreferredIP != "" {
+		errs["ip"] = validation.Validate(req.PreferredIP,
+			is.IP,
+		)
+	}
+
+	validateStreamCommandRequest(req.Env, req.Args, "", errs)
+
+	if req.Image != "" {
+		errs["image"] = validation.Validate(req.Image,
+			validation.Length(1, 255),
+			validation.Match(dockerImageRegexp),
+		)
+	}
+
+	if req.Limits != nil {
+		errs["limits.cpu"] = validation.Validate(req.Limits.CPU,
+			validation.Min(0.0),
+		)
+		errs["limits.memory"] = validation.Validate(req.Limits.Memory,
+			validation.Min(0),
+		)
+		errs["limits.pids"] = validation.Validate(req.Limits.PIDs,
+			validation.Min(0),
+		)
+	}
+
+	return errs.Filter()
+}
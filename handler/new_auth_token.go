@@ -4,7 +4,6 @@ import (
 	"net/http"
 
 	"github.com/code-cord/cc.core.server/handler/middleware"
-	"github.com/code-cord/cc.core.server/handler/models"
 	"github.com/gorilla/mux"
 )
 
@@ -22,10 +21,5 @@ func (h *Router) newAuthToken(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	resp := models.AuthorizationInfo{
-		AccessToken: authInfo.AccessToken,
-		Type:        authInfo.Type,
-	}
-
-	middleware.WriteJSONResponse(w, http.StatusCreated, resp)
+	middleware.WriteJSONResponse(w, http.StatusCreated, buildAuthorizationInfo(authInfo))
 }
@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/code-cord/cc.core.server/handler/middleware"
+	"github.com/code-cord/cc.core.server/handler/models"
+	"github.com/gorilla/mux"
+)
+
+func (h *Router) refreshToken(w http.ResponseWriter, r *http.Request) {
+	var req models.RefreshTokenRequest
+	if err := middleware.ParseJSONRequest(r, &req); err != nil {
+		middleware.WriteJSONResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	streamUUID := mux.Vars(r)["uuid"]
+
+	authInfo, err := h.server.RefreshStreamToken(r.Context(), streamUUID, req.RefreshToken)
+	if err != nil {
+		middleware.WriteJSONResponse(w, http.StatusUnauthorized,
+			middleware.ErrRefreshStreamToken.New(err.Error()))
+		return
+	}
+
+	middleware.WriteJSONResponse(w, http.StatusOK, buildAuthorizationInfo(authInfo))
+}
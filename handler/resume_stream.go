@@ -0,0 +1,20 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/code-cord/cc.core.server/handler/middleware"
+	"github.com/gorilla/mux"
+)
+
+func (h *Router) resumeStream(w http.ResponseWriter, r *http.Request) {
+	streamUUID := mux.Vars(r)["uuid"]
+
+	if err := h.server.ResumeStream(r.Context(), streamUUID); err != nil {
+		middleware.WriteJSONResponse(w, http.StatusInternalServerError,
+			middleware.ErrResumeStream.New(err.Error()))
+		return
+	}
+
+	middleware.WriteJSONResponse(w, http.StatusOK, nil)
+}
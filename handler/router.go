@@ -2,6 +2,7 @@ package handler
 
 import (
 	"crypto/rsa"
+	"net"
 	"net/http"
 
 	"github.com/code-cord/cc.core.server/handler/middleware"
@@ -12,7 +13,8 @@ import (
 // Router represents server router implementation model.
 type Router struct {
 	*mux.Router
-	server service.Server
+	server         service.Server
+	trustedProxies []*net.IPNet
 }
 
 // Config represents router configuration model.
@@ -20,13 +22,15 @@ type Config struct {
 	Server               service.Server
 	SeverSecurityEnabled bool
 	ServerPublicKey      *rsa.PublicKey
+	TrustedProxies       []*net.IPNet
 }
 
 // New returns new Router instance.
 func New(cfg Config) Router {
 	r := Router{
-		Router: mux.NewRouter(),
-		server: cfg.Server,
+		Router:         mux.NewRouter(),
+		server:         cfg.Server,
+		trustedProxies: cfg.TrustedProxies,
 	}
 
 	// public endpoints.
@@ -50,12 +54,18 @@ func New(cfg Config) Router {
 	r.Path("/stream/{uuid}/join").
 		Methods(http.MethodPost).
 		HandlerFunc(r.joinStream)
+	r.Path("/stream/{uuid}/token/refresh").
+		Methods(http.MethodPost).
+		HandlerFunc(r.refreshToken)
 
 	// server secure endpoints.
 	serverSecureRouter := r.NewRoute().Subrouter()
 	serverSecureRouter.Path("/stream").Subrouter().
 		Methods(http.MethodPost).
 		HandlerFunc(r.createStream)
+	serverSecureRouter.Path("/stream/queue/{ticket}").
+		Methods(http.MethodGet).
+		HandlerFunc(r.getStreamQueueTicket)
 	if cfg.SeverSecurityEnabled {
 		serverSecureRouter.Path("/stream/{uuid}/token").
 			Methods(http.MethodGet).
@@ -71,22 +81,57 @@ func New(cfg Config) Router {
 		Methods(http.MethodGet).
 		HandlerFunc(r.getStreamParticipants)
 	streamSecureRouter.Path(`/stream/{uuid}/service/{route:[a-zA-Z0-9=\-\/]+}`).
+		Methods(http.MethodGet, http.MethodHead, http.MethodOptions).
 		HandlerFunc(r.streamProxy)
 	streamSecureRouter.Path("/stream/{uuid}/participants/me").
 		Methods(http.MethodPatch).
 		HandlerFunc(r.patchParticipant)
 
-	streamSecureHostRouter := r.NewRoute().Subrouter()
-	streamSecureHostRouter.Use(middleware.StreamAuthMiddleware(cfg.Server, true))
-	streamSecureHostRouter.Path("/stream/{uuid}/participants/{participantUUID}/decision").
+	// viewers have read-only access to the stream service.
+	streamSecureEditorRouter := r.NewRoute().Subrouter()
+	streamSecureEditorRouter.Use(middleware.StreamRoleAuthMiddleware(cfg.Server,
+		service.ParticipantRoleHost,
+		service.ParticipantRoleCoHost,
+		service.ParticipantRoleEditor,
+	))
+	streamSecureEditorRouter.Path(`/stream/{uuid}/service/{route:[a-zA-Z0-9=\-\/]+}`).
+		HandlerFunc(r.streamProxy)
+
+	streamSecureCoHostRouter := r.NewRoute().Subrouter()
+	streamSecureCoHostRouter.Use(middleware.StreamRoleAuthMiddleware(cfg.Server,
+		service.ParticipantRoleHost,
+		service.ParticipantRoleCoHost,
+	))
+	streamSecureCoHostRouter.Path("/stream/{uuid}/participants/{participantUUID}/decision").
 		Methods(http.MethodGet).
 		HandlerFunc(r.joinParticipantDecision)
+	streamSecureCoHostRouter.Path("/stream/{uuid}/participants/{participantUUID}").
+		Methods(http.MethodDelete).
+		HandlerFunc(r.kickParticipant)
+
+	streamSecureHostRouter := r.NewRoute().Subrouter()
+	streamSecureHostRouter.Use(middleware.StreamAuthMiddleware(cfg.Server, true))
+	streamSecureHostRouter.Path("/stream/{uuid}/host").
+		Methods(http.MethodPost).
+		HandlerFunc(r.transferHost)
+	streamSecureHostRouter.Path("/stream/{uuid}/participants/{participantUUID}/role").
+		Methods(http.MethodPut).
+		HandlerFunc(r.changeParticipantRole)
+	streamSecureHostRouter.Path("/stream/{uuid}/participants/{participantUUID}/ban").
+		Methods(http.MethodPost).
+		HandlerFunc(r.banParticipant)
 	streamSecureHostRouter.Path("/stream/{uuid}").
 		Methods(http.MethodDelete).
 		HandlerFunc(r.finishStream)
 	streamSecureHostRouter.Path("/stream/{uuid}").
 		Methods(http.MethodPatch).
 		HandlerFunc(r.patchStream)
+	streamSecureHostRouter.Path("/stream/{uuid}/pause").
+		Methods(http.MethodPost).
+		HandlerFunc(r.pauseStream)
+	streamSecureHostRouter.Path("/stream/{uuid}/resume").
+		Methods(http.MethodPost).
+		HandlerFunc(r.resumeStream)
 
 	return r
 }
@@ -1,26 +1,50 @@
 package handler
 
 import (
-	"fmt"
+	"errors"
 	"net/http"
+	"net/http/httputil"
 
 	"github.com/code-cord/cc.core.server/handler/middleware"
+	"github.com/code-cord/cc.core.server/service"
 	"github.com/gorilla/mux"
 )
 
+// streamProxy proxies the participant request to the stream service.
+//
+// The stream address isn't exposed to the participants, so the requests can't bypass
+// the role checks of the server.
 func (h *Router) streamProxy(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	streamUUID := vars["uuid"]
 
 	streamAddress, err := h.server.StreamAddress(r.Context(), streamUUID)
+	if errors.Is(err, service.ErrStreamPaused) {
+		middleware.WriteJSONResponse(w, http.StatusLocked,
+			middleware.ErrStreamPaused.New(err.Error()))
+		return
+	}
 	if err != nil {
 		middleware.WriteJSONResponse(w, http.StatusInternalServerError,
 			middleware.ErrStreamInfo.New(err.Error()))
 		return
 	}
 
-	route := vars["route"]
-	redirectURL := fmt.Sprintf("http://%s/%s", streamAddress, route)
+	proxy := httputil.ReverseProxy{
+		Director: func(req *http.Request) {
+			req.URL.Scheme = "http"
+			req.URL.Host = streamAddress
+			req.URL.Path = "/" + vars["route"]
+			req.URL.RawPath = ""
+			req.Host = streamAddress
 
-	http.Redirect(w, r, redirectURL, http.StatusPermanentRedirect)
+			// the access token of the participant isn't passed to the stream.
+			req.Header.Del("Authorization")
+		},
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			middleware.WriteJSONResponse(w, http.StatusBadGateway,
+				middleware.ErrStreamInfo.New(err.Error()))
+		},
+	}
+	proxy.ServeHTTP(w, r)
 }
@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/code-cord/cc.core.server/handler/middleware"
+	"github.com/code-cord/cc.core.server/handler/models"
+	"github.com/gorilla/mux"
+)
+
+func (h *Router) transferHost(w http.ResponseWriter, r *http.Request) {
+	var req models.TransferHostRequest
+	if err := middleware.ParseJSONRequest(r, &req); err != nil {
+		middleware.WriteJSONResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	streamUUID := mux.Vars(r)["uuid"]
+
+	authInfo, err := h.server.TransferStreamHost(r.Context(), streamUUID, req.ParticipantUUID)
+	if err != nil {
+		middleware.WriteJSONResponse(w, http.StatusInternalServerError,
+			middleware.ErrTransferStreamHost.New(err.Error()))
+		return
+	}
+
+	host, err := h.server.Participant(r.Context(), streamUUID, req.ParticipantUUID)
+	if err != nil {
+		middleware.WriteJSONResponse(w, http.StatusInternalServerError,
+			middleware.ErrTransferStreamHost.New(err.Error()))
+		return
+	}
+
+	middleware.WriteJSONResponse(w, http.StatusOK, models.TransferHostResponse{
+		HostInfo: models.HostOwnerInfo{
+			UUID:     host.UUID,
+			Username: host.Name,
+			AvatarID: host.AvatarID,
+			IP:       host.IP,
+		},
+		Auth: buildAuthorizationInfo(authInfo),
+	})
+}
@@ -0,0 +1,25 @@
+package worker
+
+import (
+	"net/http"
+
+	"github.com/code-cord/cc.core.server/handler/middleware"
+	"github.com/code-cord/cc.core.server/handler/models"
+	"github.com/gorilla/mux"
+)
+
+func (h *Router) getStream(w http.ResponseWriter, r *http.Request) {
+	streamUUID := mux.Vars(r)["uuid"]
+
+	startInfo, err := h.worker.WorkerStream(r.Context(), streamUUID)
+	if err != nil {
+		middleware.WriteJSONResponse(w, errStatus(err), middleware.ErrWorkerStream.New(err.Error()))
+		return
+	}
+
+	middleware.WriteJSONResponse(w, http.StatusOK, models.WorkerStreamResponse{
+		UUID: streamUUID,
+		IP:   startInfo.IP,
+		Port: startInfo.Port,
+	})
+}
@@ -0,0 +1,54 @@
+package worker
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/code-cord/cc.core.server/handler/middleware"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	streamLogsBufferSize = 32 * 1024
+)
+
+func (h *Router) streamLogs(w http.ResponseWriter, r *http.Request) {
+	streamUUID := mux.Vars(r)["uuid"]
+
+	logs, err := h.worker.StreamLogs(r.Context(), streamUUID)
+	if err != nil {
+		middleware.WriteJSONResponse(w, errStatus(err),
+			middleware.ErrWorkerStreamLogs.New(err.Error()))
+		return
+	}
+	defer logs.Close()
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		middleware.WriteJSONResponse(w, http.StatusBadRequest, middleware.ErrSSEUpgrade.New(nil))
+		return
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	// the output is streamed as is until the stream exits or the server disconnects.
+	buf := make([]byte, streamLogsBufferSize)
+	for {
+		n, err := logs.Read(buf)
+		if n > 0 {
+			if _, err := w.Write(buf[:n]); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+
+		if err != nil {
+			if err != io.EOF && r.Context().Err() == nil {
+				logrus.Errorf("could not read %s stream logs: %v", streamUUID, err)
+			}
+			return
+		}
+	}
+}
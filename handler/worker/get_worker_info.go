@@ -0,0 +1,20 @@
+package worker
+
+import (
+	"net/http"
+
+	"github.com/code-cord/cc.core.server/handler/middleware"
+	"github.com/code-cord/cc.core.server/handler/models"
+)
+
+func (h *Router) getWorkerInfo(w http.ResponseWriter, r *http.Request) {
+	info := h.worker.Info()
+
+	middleware.WriteJSONResponse(w, http.StatusOK, models.WorkerInfoResponse{
+		Name:       info.Name,
+		Version:    info.Version,
+		LaunchMode: info.LaunchMode,
+		Capacity:   info.Capacity,
+		Streams:    info.Streams,
+	})
+}
@@ -0,0 +1,20 @@
+package worker
+
+import (
+	"net/http"
+
+	"github.com/code-cord/cc.core.server/handler/middleware"
+	"github.com/gorilla/mux"
+)
+
+func (h *Router) pauseStream(w http.ResponseWriter, r *http.Request) {
+	streamUUID := mux.Vars(r)["uuid"]
+
+	if err := h.worker.PauseStream(r.Context(), streamUUID); err != nil {
+		middleware.WriteJSONResponse(w, errStatus(err),
+			middleware.ErrWorkerPauseStream.New(err.Error()))
+		return
+	}
+
+	middleware.WriteJSONResponse(w, http.StatusOK, nil)
+}
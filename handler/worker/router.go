@@ -0,0 +1,73 @@
+package worker
+
+import (
+	"net/http"
+	"os"
+
+	"github.com/code-cord/cc.core.server/handler/middleware"
+	"github.com/code-cord/cc.core.server/service"
+	"github.com/gorilla/mux"
+)
+
+// Router represents worker router implementation model.
+type Router struct {
+	*mux.Router
+	worker service.Worker
+}
+
+// Config represents router configuration model.
+type Config struct {
+	Worker service.Worker
+	Token  string
+}
+
+// New returns new router instance.
+func New(cfg Config) Router {
+	r := Router{
+		Router: mux.NewRouter(),
+		worker: cfg.Worker,
+	}
+	r.Use(middleware.WorkerAuthMiddleware(cfg.Token))
+
+	r.Path("/").
+		Methods(http.MethodGet).
+		HandlerFunc(r.getWorkerInfo)
+
+	r.Path("/stream").
+		Methods(http.MethodPost).
+		HandlerFunc(r.startStream)
+
+	r.Path("/stream/{uuid}").
+		Methods(http.MethodGet).
+		HandlerFunc(r.getStream)
+
+	r.Path("/stream/{uuid}").
+		Methods(http.MethodDelete).
+		HandlerFunc(r.stopStream)
+
+	r.Path("/stream/{uuid}/pause").
+		Methods(http.MethodPost).
+		HandlerFunc(r.pauseStream)
+
+	r.Path("/stream/{uuid}/resume").
+		Methods(http.MethodPost).
+		HandlerFunc(r.resumeStream)
+
+	r.Path("/stream/{uuid}/logs").
+		Methods(http.MethodGet).
+		HandlerFunc(r.streamLogs)
+
+	r.Path("/stream/{uuid}/wait").
+		Methods(http.MethodGet).
+		HandlerFunc(r.waitStream)
+
+	return r
+}
+
+func errStatus(err error) int {
+	if os.IsNotExist(err) {
+		return http.StatusNotFound
+	}
+
+	return http.StatusInternalServerError
+}
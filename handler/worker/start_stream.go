@@ -0,0 +1,51 @@
+package worker
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/code-cord/cc.core.server/handler/middleware"
+	"github.com/code-cord/cc.core.server/handler/models"
+	"github.com/code-cord/cc.core.server/service"
+)
+
+func (h *Router) startStream(w http.ResponseWriter, r *http.Request) {
+	var req models.WorkerStartStreamRequest
+	if err := middleware.ParseJSONRequest(r, &req); err != nil {
+		middleware.WriteJSONResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	cfg := service.StreamLaunchConfig{
+		PreferredPort: req.PreferredPort,
+		PreferredIP:   req.PreferredIP,
+		Env:           req.Env,
+		Args:          req.Args,
+		Image:         req.Image,
+	}
+	if req.Limits != nil {
+		cfg.Limits = service.StreamResourceLimits{
+			CPU:    req.Limits.CPU,
+			Memory: req.Limits.Memory,
+			PIDs:   req.Limits.PIDs,
+		}
+	}
+
+	startInfo, err := h.worker.StartStream(r.Context(), req.UUID, cfg)
+	if errors.Is(err, service.ErrWorkerFull) {
+		middleware.WriteJSONResponse(w, http.StatusServiceUnavailable,
+			middleware.ErrWorkerFull.New(err.Error()))
+		return
+	}
+	if err != nil {
+		middleware.WriteJSONResponse(w, http.StatusInternalServerError,
+			middleware.ErrWorkerStartStream.New(err.Error()))
+		return
+	}
+
+	middleware.WriteJSONResponse(w, http.StatusCreated, models.WorkerStreamResponse{
+		UUID: req.UUID,
+		IP:   startInfo.IP,
+		Port: startInfo.Port,
+	})
+}
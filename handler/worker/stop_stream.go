@@ -0,0 +1,20 @@
+package worker
+
+import (
+	"net/http"
+
+	"github.com/code-cord/cc.core.server/handler/middleware"
+	"github.com/gorilla/mux"
+)
+
+func (h *Router) stopStream(w http.ResponseWriter, r *http.Request) {
+	streamUUID := mux.Vars(r)["uuid"]
+
+	if err := h.worker.StopStream(r.Context(), streamUUID); err != nil {
+		middleware.WriteJSONResponse(w, errStatus(err),
+			middleware.ErrWorkerStopStream.New(err.Error()))
+		return
+	}
+
+	middleware.WriteJSONResponse(w, http.StatusOK, nil)
+}
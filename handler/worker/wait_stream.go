@@ -0,0 +1,25 @@
+package worker
+
+import (
+	"net/http"
+
+	"github.com/code-cord/cc.core.server/handler/middleware"
+	"github.com/code-cord/cc.core.server/handler/models"
+	"github.com/gorilla/mux"
+)
+
+// waitStream responds once the stream exits.
+func (h *Router) waitStream(w http.ResponseWriter, r *http.Request) {
+	streamUUID := mux.Vars(r)["uuid"]
+
+	exit, err := h.worker.WaitStream(r.Context(), streamUUID)
+	if err != nil {
+		middleware.WriteJSONResponse(w, errStatus(err),
+			middleware.ErrWorkerWaitStream.New(err.Error()))
+		return
+	}
+
+	middleware.WriteJSONResponse(w, http.StatusOK, models.WorkerStreamExitResponse{
+		Error: exit.Error,
+	})
+}
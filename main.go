@@ -7,6 +7,7 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/code-cord/cc.core.server/server"
 	"github.com/sirupsen/logrus"
@@ -20,6 +21,9 @@ const (
 	codeCordBinPathEnv              = "CODE_CORD_PATH"
 	codeCordServerPublicKeyPathEnv  = "CODE_CORD_SERVER_PUBLIC_KEY"
 	codeCordServerPrivateKeyPathEnv = "CODE_CORD_SERVER_PRIVATE_KEY"
+	codeCordMasterKeyPathEnv        = "CODE_CORD_MASTER_KEY"
+	codeCordMasterKeySecretEnv      = "CODE_CORD_MASTER_KEY_SECRET"
+	codeCordWorkerTokenEnv          = "CODE_CORD_WORKER_TOKEN"
 
 	defaultStreamPrefixContainer = "code-cord.stream"
 	defaultStreamImage           = "code-cord.stream"
@@ -43,6 +47,33 @@ type serverConfig struct {
 	securityPublicKeyPath   string
 	securityPrivateKeyPath  string
 	binariesPath            string
+	masterKeyPath           string
+	masterKeySecret         string
+	healthCheckInterval     time.Duration
+	healthCheckRoute        string
+	unhealthyThreshold      time.Duration
+	streamStopTimeout       time.Duration
+	streamLogMaxSize        int64
+	streamCPULimit          float64
+	streamMemoryLimit       int64
+	streamPIDsLimit         int64
+	streamCgroupParent      string
+	streamFinishWarning     time.Duration
+	workers                 cli.StringSlice
+	workerToken             string
+	workerTLS               bool
+	workerCACertFile        string
+	streamPortRange         string
+	allowedStreamImages     cli.StringSlice
+	trustedProxies          cli.StringSlice
+	maxStreams              int
+	maxSubjectStreams       int
+	maxSubjectStreamTime    time.Duration
+	quotaPeriod             time.Duration
+	streamTokenTTL          time.Duration
+	streamRefreshTokenTTL   time.Duration
+	joinApprovalTimeout     time.Duration
+	streamQueueTTL          time.Duration
 }
 
 func main() {
@@ -211,6 +242,198 @@ func main() {
 					codeCordServerPrivateKeyPathEnv,
 				},
 			},
+			&cli.PathFlag{
+				Name:        "master-key",
+				Usage:       "Path to server master key file used to encrypt stream access keys",
+				Required:    false,
+				TakesFile:   true,
+				Destination: &cfg.masterKeyPath,
+				DefaultText: "master.key file in the data folder",
+				EnvVars: []string{
+					codeCordMasterKeyPathEnv,
+				},
+			},
+			&cli.StringFlag{
+				Name:        "master-key-secret",
+				Usage:       "Server master key used to encrypt stream access keys, takes precedence over the master key file",
+				Required:    false,
+				Destination: &cfg.masterKeySecret,
+				EnvVars: []string{
+					codeCordMasterKeySecretEnv,
+				},
+			},
+			&cli.DurationFlag{
+				Name:        "health-check-interval",
+				Usage:       "Interval between stream health checks (negative value disables checks)",
+				Required:    false,
+				Destination: &cfg.healthCheckInterval,
+				DefaultText: "30s",
+			},
+			&cli.StringFlag{
+				Name:        "health-check-route",
+				Usage:       "Stream HTTP route to check stream health",
+				Required:    false,
+				Destination: &cfg.healthCheckRoute,
+				DefaultText: "TCP connection check only",
+			},
+			&cli.DurationFlag{
+				Name:        "unhealthy-threshold",
+				Usage:       "Duration after which constantly unreachable stream will be finished",
+				Required:    false,
+				Destination: &cfg.unhealthyThreshold,
+				DefaultText: "never finish",
+			},
+			&cli.DurationFlag{
+				Name:        "stream-stop-timeout",
+				Usage:       "Grace period for the stream to exit before it is killed",
+				Required:    false,
+				Destination: &cfg.streamStopTimeout,
+				DefaultText: "10s",
+			},
+			&cli.Int64Flag{
+				Name:        "stream-log-max-size",
+				Usage:       "Max size of the stream log file in bytes before it is rotated",
+				Required:    false,
+				Destination: &cfg.streamLogMaxSize,
+				DefaultText: "10MB",
+			},
+			&cli.Float64Flag{
+				Name:        "stream-cpu-limit",
+				Usage:       "Max number of CPUs available to a stream",
+				Required:    false,
+				Destination: &cfg.streamCPULimit,
+				DefaultText: "unlimited",
+			},
+			&cli.Int64Flag{
+				Name:        "stream-memory-limit",
+				Usage:       "Max memory available to a stream in bytes",
+				Required:    false,
+				Destination: &cfg.streamMemoryLimit,
+				DefaultText: "unlimited",
+			},
+			&cli.Int64Flag{
+				Name:        "stream-pids-limit",
+				Usage:       "Max number of processes available to a stream",
+				Required:    false,
+				Destination: &cfg.streamPIDsLimit,
+				DefaultText: "unlimited",
+			},
+			&cli.StringFlag{
+				Name:        "stream-cgroup-parent",
+				Usage:       "Parent cgroup of standalone streams relative to the cgroup v2 root",
+				Required:    false,
+				Destination: &cfg.streamCgroupParent,
+				DefaultText: "code-cord",
+			},
+			&cli.DurationFlag{
+				Name:        "stream-finish-warning",
+				Usage:       "How long before finishing expired or idle stream it is warned",
+				Required:    false,
+				Destination: &cfg.streamFinishWarning,
+				DefaultText: "5m",
+			},
+			&cli.StringSliceFlag{
+				Name:        "worker",
+				Usage:       "Address of the remote stream worker, may be set multiple times",
+				Required:    false,
+				Destination: &cfg.workers,
+			},
+			&cli.StringFlag{
+				Name:        "worker-token",
+				Usage:       "Token shared with the remote stream workers",
+				Required:    false,
+				Destination: &cfg.workerToken,
+				EnvVars: []string{
+					codeCordWorkerTokenEnv,
+				},
+			},
+			&cli.BoolFlag{
+				Name:        "worker-tls",
+				Usage:       "Request the remote stream workers over https",
+				Required:    false,
+				Destination: &cfg.workerTLS,
+			},
+			&cli.PathFlag{
+				Name:        "worker-ca-cert",
+				Usage:       "Path to the CA certificate of the remote stream workers",
+				Required:    false,
+				Destination: &cfg.workerCACertFile,
+				DefaultText: "system roots",
+			},
+			&cli.StringFlag{
+				Name:        "stream-port-range",
+				Usage:       "Range of the ports in min-max format the streams are bound to",
+				Required:    false,
+				Destination: &cfg.streamPortRange,
+			},
+			&cli.StringSliceFlag{
+				Name:        "allowed-stream-image",
+				Usage:       "Docker image the streams may request, may be set multiple times",
+				Required:    false,
+				Destination: &cfg.allowedStreamImages,
+			},
+			&cli.StringSliceFlag{
+				Name:        "trusted-proxy",
+				Usage:       "IP or CIDR range of the proxy allowed to set X-Forwarded-For header, may be set multiple times",
+				Required:    false,
+				Destination: &cfg.trustedProxies,
+			},
+			&cli.IntFlag{
+				Name:        "max-streams",
+				Usage:       "Max number of the running streams",
+				Required:    false,
+				Destination: &cfg.maxStreams,
+				DefaultText: "no limit",
+			},
+			&cli.IntFlag{
+				Name:        "max-subject-streams",
+				Usage:       "Max number of the running streams created by the same token subject",
+				Required:    false,
+				Destination: &cfg.maxSubjectStreams,
+				DefaultText: "no limit",
+			},
+			&cli.DurationFlag{
+				Name:        "max-subject-stream-time",
+				Usage:       "Max total time of the streams created by the same token subject within the quota period",
+				Required:    false,
+				Destination: &cfg.maxSubjectStreamTime,
+				DefaultText: "no limit",
+			},
+			&cli.DurationFlag{
+				Name:        "quota-period",
+				Usage:       "Period the stream time of the token subject is counted within",
+				Required:    false,
+				Destination: &cfg.quotaPeriod,
+				DefaultText: "720h",
+			},
+			&cli.DurationFlag{
+				Name:        "stream-token-ttl",
+				Usage:       "Lifetime of the stream access tokens",
+				Required:    false,
+				Destination: &cfg.streamTokenTTL,
+				DefaultText: "15m",
+			},
+			&cli.DurationFlag{
+				Name:        "stream-refresh-token-ttl",
+				Usage:       "Lifetime of the stream refresh tokens",
+				Required:    false,
+				Destination: &cfg.streamRefreshTokenTTL,
+				DefaultText: "24h",
+			},
+			&cli.DurationFlag{
+				Name:        "join-approval-timeout",
+				Usage:       "How long join requests wait for the host approval",
+				Required:    false,
+				Destination: &cfg.joinApprovalTimeout,
+				DefaultText: "5m",
+			},
+			&cli.DurationFlag{
+				Name:        "stream-queue-ttl",
+				Usage:       "How long queued streams wait for the stream quotas",
+				Required:    false,
+				Destination: &cfg.streamQueueTTL,
+				DefaultText: "1h",
+			},
 		},
 	}
 	if err := app.Run(os.Args); err != nil {
@@ -222,7 +445,7 @@ func main() {
 		logrus.Fatalf("could not create server instance: %v", err)
 	}
 
-	c := make(chan os.Signal)
+	c := make(chan os.Signal, 1)
 	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
 	go func() {
 		<-c
@@ -263,5 +486,25 @@ func newServer(cfg serverConfig) (*server.Server, error) {
 		server.ServerSecurityEnabled(cfg.withSecurityCheck),
 		server.ServerPrivateKey(cfg.securityPrivateKeyPath),
 		server.ServerPublicKey(cfg.securityPublicKeyPath),
+		server.MasterKey(cfg.masterKeyPath),
+		server.MasterKeySecret(cfg.masterKeySecret),
+		server.HealthCheckInterval(cfg.healthCheckInterval),
+		server.HealthCheckRoute(cfg.healthCheckRoute),
+		server.UnhealthyThreshold(cfg.unhealthyThreshold),
+		server.StreamStopTimeout(cfg.streamStopTimeout),
+		server.StreamLogMaxSize(cfg.streamLogMaxSize),
+		server.StreamResourceLimits(cfg.streamCPULimit, cfg.streamMemoryLimit, cfg.streamPIDsLimit),
+		server.StreamCgroupParent(cfg.streamCgroupParent),
+		server.StreamFinishWarning(cfg.streamFinishWarning),
+		server.RemoteWorkers(cfg.workerToken, cfg.workers.Value()...),
+		server.RemoteWorkersTLS(cfg.workerTLS, cfg.workerCACertFile),
+		server.StreamPortRange(cfg.streamPortRange),
+		server.AllowedStreamImages(cfg.allowedStreamImages.Value()...),
+		server.StreamQuota(cfg.maxStreams, cfg.maxSubjectStreams,
+			cfg.maxSubjectStreamTime, cfg.quotaPeriod),
+		server.StreamTokenTTL(cfg.streamTokenTTL, cfg.streamRefreshTokenTTL),
+		server.JoinApprovalTimeout(cfg.joinApprovalTimeout),
+		server.StreamQueueTTL(cfg.streamQueueTTL),
+		server.TrustedProxies(cfg.trustedProxies.Value()...),
 	)
 }
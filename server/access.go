@@ -0,0 +1,97 @@
+package server
+
+import (
+	"sync"
+
+	"github.com/code-cord/cc.core.server/service"
+)
+
+// streamAccessCache represents cache of the data checked on every participant request:
+// participants of the stream along with the host and revoked tokens.
+//
+// The cache is filled from the storages on demand and dropped on every write of the
+// cached data, so the requests don't hit the storages until the access is changed.
+type streamAccessCache struct {
+	mu         sync.Mutex
+	generation uint64
+	streams    map[string]*streamAccess
+}
+
+// streamAccess represents cached access data of the stream.
+type streamAccess struct {
+	participants map[string]service.Participant
+	revoked      revokedStreamTokens
+}
+
+func newStreamAccessCache() *streamAccessCache {
+	return &streamAccessCache{
+		streams: make(map[string]*streamAccess),
+	}
+}
+
+// invalidate drops cached access data of the stream, it must be called after
+// the data is stored.
+func (c *streamAccessCache) invalidate(streamUUID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	delete(c.streams, streamUUID)
+}
+
+// streamAccess returns access data of the stream loading it from the storages if needed.
+func (s *Server) streamAccess(streamUUID string) (*streamAccess, error) {
+	s.access.mu.Lock()
+	access, ok := s.access.streams[streamUUID]
+	generation := s.access.generation
+	s.access.mu.Unlock()
+	if ok {
+		return access, nil
+	}
+
+	host, err := s.streamHost(streamUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	participants, err := s.loadParticipants(streamUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	revoked, err := s.revokedStreamTokens(streamUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	access = &streamAccess{
+		participants: map[string]service.Participant{
+			host.UUID: *host,
+		},
+		revoked: revoked,
+	}
+	for i := range participants {
+		p := &participants[i]
+		if p.UUID == host.UUID {
+			continue
+		}
+
+		access.participants[p.UUID] = service.Participant{
+			UUID:     p.UUID,
+			Name:     p.Name,
+			AvatarID: p.AvatarID,
+			IP:       p.IP,
+			Status:   p.Status,
+			Role:     participantRole(p),
+		}
+	}
+
+	// the data loaded before the last write isn't cached, it may be stale already.
+	s.access.mu.Lock()
+	if s.access.generation == generation {
+		s.access.streams[streamUUID] = access
+	}
+	s.access.mu.Unlock()
+
+	return access, nil
+}
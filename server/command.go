@@ -0,0 +1,57 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/code-cord/cc.core.server/service"
+	"github.com/code-cord/cc.core.server/util"
+)
+
+// checkStreamEnv makes sure the environment variables requested for the stream can't
+// hijack the stream process.
+//
+// Remote worker streams are checked as well, since the worker may launch them as
+// standalone apps.
+func checkStreamEnv(cfg service.StreamLaunchConfig) error {
+	switch cfg.Mode {
+	case "", service.StreamLaunchModeStandaloneApp, service.StreamLaunchModeRemoteWorker:
+	default:
+		return nil
+	}
+
+	for name := range cfg.Env {
+		if util.IsUnsafeEnv(name) {
+			return fmt.Errorf("%w: %s may hijack the stream process",
+				service.ErrForbiddenStreamEnv, name)
+		}
+	}
+
+	return nil
+}
+
+// streamServerFlags are flags of the stream instance set by the server only.
+var streamServerFlags = []string{
+	"addr",
+}
+
+// checkStreamArgs makes sure the arguments requested for the stream don't override
+// the flags set by the server, e.g. the stream can't listen on an address the server
+// doesn't serve.
+func checkStreamArgs(cfg service.StreamLaunchConfig) error {
+	for _, arg := range cfg.Args {
+		if !strings.HasPrefix(arg, "-") {
+			continue
+		}
+
+		name := strings.SplitN(strings.TrimLeft(arg, "-"), "=", 2)[0]
+		for _, flag := range streamServerFlags {
+			if name == flag {
+				return fmt.Errorf("%w: %s flag is set by the server",
+					service.ErrForbiddenStreamArg, flag)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package server
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/code-cord/cc.core.server/service"
+)
+
+func TestCheckStreamEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    service.StreamLaunchMode
+		env     map[string]string
+		wantErr bool
+	}{
+		{
+			name: "stream environment",
+			mode: service.StreamLaunchModeStandaloneApp,
+			env:  map[string]string{"STREAM_THEME": "dark"},
+		},
+		{
+			name:    "preloaded library of the default mode",
+			env:     map[string]string{"LD_PRELOAD": "/tmp/hook.so"},
+			wantErr: true,
+		},
+		{
+			name:    "library path of the standalone stream",
+			mode:    service.StreamLaunchModeStandaloneApp,
+			env:     map[string]string{"ld_library_path": "/tmp"},
+			wantErr: true,
+		},
+		{
+			name:    "path of the remote worker stream",
+			mode:    service.StreamLaunchModeRemoteWorker,
+			env:     map[string]string{"PATH": "/tmp"},
+			wantErr: true,
+		},
+		{
+			name: "path of the docker container stream",
+			mode: service.StreamLaunchModeDockerContainer,
+			env:  map[string]string{"PATH": "/usr/local/bin:/usr/bin"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkStreamEnv(service.StreamLaunchConfig{
+				Mode: tt.mode,
+				Env:  tt.env,
+			})
+			if tt.wantErr && !errors.Is(err, service.ErrForbiddenStreamEnv) {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("could not check stream environment: %v", err)
+			}
+		})
+	}
+}
+
+func TestCheckStreamArgs(t *testing.T) {
+	allowed := [][]string{
+		nil,
+		{"-theme", "dark"},
+		{"--address-book=contacts", "addr"},
+	}
+	for _, args := range allowed {
+		if err := checkStreamArgs(service.StreamLaunchConfig{Args: args}); err != nil {
+			t.Errorf("could not check %v arguments: %v", args, err)
+		}
+	}
+
+	forbidden := [][]string{
+		{"-addr", "0.0.0.0:80"},
+		{"--addr", "0.0.0.0:80"},
+		{"-theme", "dark", "-addr=0.0.0.0:80"},
+		{"--addr=0.0.0.0:80"},
+	}
+	for _, args := range forbidden {
+		err := checkStreamArgs(service.StreamLaunchConfig{Args: args})
+		if !errors.Is(err, service.ErrForbiddenStreamArg) {
+			t.Errorf("%v arguments are accepted: %v", args, err)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/code-cord/cc.core.server/service"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultHealthCheckInterval = 30 * time.Second
+	defaultHealthCheckTimeout  = 5 * time.Second
+)
+
+var errStreamNotRunning = errors.New("stream is not running")
+
+type streamHealthState struct {
+	health         service.StreamHealth
+	unhealthySince time.Time
+}
+
+// runHealthChecks periodically probes all running streams until the server is stopped.
+func (s *Server) runHealthChecks() {
+	if s.opts.HealthCheckInterval < 0 {
+		logrus.Warn("stream health checks are disabled")
+		return
+	}
+
+	ticker := time.NewTicker(s.opts.HealthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.checkStreamsHealth()
+		case <-s.done:
+			return
+		}
+	}
+}
+
+func (s *Server) checkStreamsHealth() {
+	var wg sync.WaitGroup
+	s.streams.Range(func(key, value interface{}) bool {
+		wg.Add(1)
+		go func(streamUUID string, module *streamModule) {
+			defer wg.Done()
+
+			s.checkStreamHealth(streamUUID, module)
+		}(key.(string), value.(*streamModule))
+
+		return true
+	})
+	wg.Wait()
+}
+
+func (s *Server) checkStreamHealth(streamUUID string, module *streamModule) {
+	// paused stream doesn't respond by design.
+	if module.isPaused() {
+		return
+	}
+
+	health := probeStream(s.healthClient, module.address(), s.opts.HealthCheckRoute)
+	changed, unhealthyFor := module.setHealth(health, time.Now())
+
+	if changed {
+		logrus.Infof("stream %s health has been changed to %s", streamUUID, health)
+
+		if err := s.updateStreamHealth(streamUUID, health); err != nil {
+			logrus.Errorf("could not store %s stream health: %v", streamUUID, err)
+		}
+	}
+
+	threshold := s.opts.UnhealthyThreshold
+	if threshold > 0 && unhealthyFor >= threshold {
+		logrus.Warnf("stream %s has been unhealthy for %v, finishing it", streamUUID, unhealthyFor)
+		s.killStream(context.Background(), streamUUID)
+	}
+}
+
+func (s *Server) updateStreamHealth(streamUUID string, health service.StreamHealth) error {
+	_, err := s.updateStreamInfo(streamUUID, func(info *streamInfo) error {
+		if info.Status != service.StreamStatusRunning {
+			return errStreamNotRunning
+		}
+		info.Health = health
+
+		return nil
+	})
+	if err == errStreamFinished || err == errStreamNotRunning {
+		return nil
+	}
+
+	return err
+}
+
+// setHealth sets current health of the stream.
+//
+// It reports whether the health has been changed and for how long the stream is unhealthy.
+// Degraded stream still serves the participants, so only unreachable stream is unhealthy.
+func (m *streamModule) setHealth(health service.StreamHealth, now time.Time) (
+	changed bool, unhealthyFor time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	changed = m.healthState.health != health
+	m.healthState.health = health
+
+	if health != service.StreamHealthUnreachable {
+		m.healthState.unhealthySince = time.Time{}
+		return
+	}
+
+	if m.healthState.unhealthySince.IsZero() {
+		m.healthState.unhealthySince = now
+	}
+	unhealthyFor = now.Sub(m.healthState.unhealthySince)
+
+	return
+}
+
+func probeStream(client *http.Client, address, route string) service.StreamHealth {
+	if !isStreamReachable(address) {
+		return service.StreamHealthUnreachable
+	}
+
+	if route == "" {
+		return service.StreamHealthHealthy
+	}
+
+	resp, err := client.Get(fmt.Sprintf("http://%s/%s", address, strings.TrimPrefix(route, "/")))
+	if err != nil {
+		return service.StreamHealthDegraded
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return service.StreamHealthDegraded
+	}
+
+	return service.StreamHealthHealthy
+}
@@ -0,0 +1,144 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/code-cord/cc.core.server/service"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	previousHostRole = service.ParticipantRoleCoHost
+)
+
+// TransferStreamHost promotes active participant of the stream to the host.
+//
+// The previous host stays in the stream as a co-host. Host tokens issued before
+// the transfer don't grant host access anymore.
+func (s *Server) TransferStreamHost(ctx context.Context, streamUUID, participantUUID string) (
+	*service.AuthInfo, error) {
+	unlock := s.lockStreamInfo(streamUUID)
+	defer unlock()
+
+	if _, ok := s.streams.Load(streamUUID); !ok {
+		return nil, fmt.Errorf("could not find running stream by UUID %s", streamUUID)
+	}
+
+	info, err := s.loadStreamInfo(streamUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	participants, err := s.loadParticipants(streamUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	idx := -1
+	for i := range participants {
+		if participants[i].UUID == participantUUID {
+			idx = i
+			break
+		}
+	}
+	if idx == -1 || participants[idx].Status != service.ParticipantStatusActive {
+		return nil, fmt.Errorf("could not find active participant by UUID %s", participantUUID)
+	}
+
+	keys, err := s.streamKeys(streamUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	// participants and stream info are kept in the different storages, so the stored
+	// participants are restored if the stream info can't be stored.
+	prevParticipants := make([]participantInfo, len(participants))
+	copy(prevParticipants, participants)
+
+	newHost := participants[idx]
+	prevHost := participantInfo{
+		UUID:     info.Host.UUID,
+		Name:     info.Host.Username,
+		AvatarID: info.Host.AvatarID,
+		IP:       info.Host.IP,
+		Status:   service.ParticipantStatusActive,
+		Role:     previousHostRole,
+	}
+	participants = append(participants[:idx], participants[idx+1:]...)
+	participants = append(participants, prevHost)
+
+	info.Host = streamHostInfo{
+		UUID:     newHost.UUID,
+		Username: newHost.Name,
+		AvatarID: newHost.AvatarID,
+		IP:       newHost.IP,
+	}
+
+	// the access may have been cached in between of the writes or before the rollback.
+	defer s.access.invalidate(streamUUID)
+
+	if err := s.participantStorage.Default().
+		Store(streamUUID, participants, json.Marshal); err != nil {
+		return nil, fmt.Errorf("could not store participants: %v", err)
+	}
+
+	if err := s.streamStorage.Default().Store(streamUUID, info, json.Marshal); err != nil {
+		if rollbackErr := s.participantStorage.Default().
+			Store(streamUUID, prevParticipants, json.Marshal); rollbackErr != nil {
+			logrus.Errorf("could not restore participants of %s stream: %v", streamUUID, rollbackErr)
+		}
+
+		return nil, fmt.Errorf("could not store stream info: %v", err)
+	}
+
+	token, err := s.generateStreamTokens(
+		streamUUID, newHost.UUID, service.ParticipantRoleHost, keys.privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("could not generate access token: %v", err)
+	}
+
+	go func() {
+		s.updateParticipantInfo(streamUUID, service.StreamParticipant{
+			UUID:     prevHost.UUID,
+			Name:     prevHost.Name,
+			AvatarID: prevHost.AvatarID,
+			Status:   prevHost.Status,
+			Role:     prevHost.Role,
+			Host:     false,
+		})
+		s.updateParticipantInfo(streamUUID, service.StreamParticipant{
+			UUID:     newHost.UUID,
+			Name:     newHost.Name,
+			AvatarID: newHost.AvatarID,
+			Status:   newHost.Status,
+			Role:     service.ParticipantRoleHost,
+			Host:     true,
+		})
+	}()
+
+	return token, nil
+}
+
+// streamHost returns current host of the stream as a participant.
+func (s *Server) streamHost(streamUUID string) (*service.Participant, error) {
+	streamRV := s.streamStorage.Default().Load(streamUUID)
+	if streamRV == nil {
+		return nil, fmt.Errorf("could not find stream by UUID %s", streamUUID)
+	}
+
+	var info streamInfo
+	if err := streamRV.Decode(&info, json.Unmarshal); err != nil {
+		return nil, fmt.Errorf("could not decode stream data: %v", err)
+	}
+
+	return &service.Participant{
+		UUID:     info.Host.UUID,
+		Name:     info.Host.Username,
+		AvatarID: info.Host.AvatarID,
+		IP:       info.Host.IP,
+		Status:   service.ParticipantStatusActive,
+		Role:     service.ParticipantRoleHost,
+	}, nil
+}
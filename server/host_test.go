@@ -0,0 +1,53 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/code-cord/cc.core.server/service"
+)
+
+func TestTransferStreamHost(t *testing.T) {
+	s := newTestServer(t)
+	ctx := context.Background()
+
+	stream, err := s.NewStream(ctx, testStreamConfig())
+	if err != nil {
+		t.Fatalf("could not create stream: %v", err)
+	}
+
+	_, err = s.JoinParticipant(ctx, stream.UUID, stream.JoinCode, service.Participant{
+		Name: "participant",
+	})
+	if err != nil {
+		t.Fatalf("could not join participant: %v", err)
+	}
+	participants, err := s.StreamParticipants(ctx, stream.UUID)
+	if err != nil || len(participants) != 1 {
+		t.Fatalf("could not get participants: %v", err)
+	}
+	newHostUUID := participants[0].UUID
+
+	auth, err := s.TransferStreamHost(ctx, stream.UUID, newHostUUID)
+	if err != nil {
+		t.Fatalf("could not transfer host: %v", err)
+	}
+	if auth.AccessToken == "" {
+		t.Error("host token isn't issued")
+	}
+
+	host, err := s.Participant(ctx, stream.UUID, newHostUUID)
+	if err != nil || host.Role != service.ParticipantRoleHost {
+		t.Fatalf("participant isn't promoted to the host: %+v, %v", host, err)
+	}
+
+	prevHost, err := s.Participant(ctx, stream.UUID, stream.Host.UUID)
+	if err != nil || prevHost.Role != previousHostRole {
+		t.Fatalf("previous host isn't demoted: %+v, %v", prevHost, err)
+	}
+
+	// the new host isn't listed twice.
+	if _, err := s.TransferStreamHost(ctx, stream.UUID, newHostUUID); err == nil {
+		t.Error("host is transferred to the current host")
+	}
+}
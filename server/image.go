@@ -0,0 +1,40 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/code-cord/cc.core.server/service"
+)
+
+// checkStreamImage makes sure the docker image requested for the stream is allowed.
+//
+// The default stream image is always allowed, other images have to be allowlisted.
+// Streams without the launch mode are checked as the streams of the default mode.
+func (s *Server) checkStreamImage(cfg service.StreamLaunchConfig) error {
+	if cfg.Image == "" {
+		return nil
+	}
+
+	mode := cfg.Mode
+	if mode == "" {
+		mode = defaultStreamLaunchMode
+	}
+
+	switch mode {
+	case service.StreamLaunchModeDockerContainer, service.StreamLaunchModeRemoteWorker:
+	default:
+		return fmt.Errorf("docker image can't be set for %s streams", mode)
+	}
+
+	if cfg.Image == s.opts.StreamImage {
+		return nil
+	}
+
+	for _, image := range s.opts.AllowedStreamImages {
+		if image == cfg.Image {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("stream image %s is not allowed", cfg.Image)
+}
@@ -0,0 +1,75 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/code-cord/cc.core.server/service"
+)
+
+func TestCheckStreamImage(t *testing.T) {
+	s := Server{
+		opts: Options{
+			StreamImage:         "codecord/stream",
+			AllowedStreamImages: []string{"codecord/stream:beta"},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		cfg     service.StreamLaunchConfig
+		wantErr bool
+	}{
+		{
+			name: "default image",
+			cfg: service.StreamLaunchConfig{
+				Mode:  service.StreamLaunchModeDockerContainer,
+				Image: "codecord/stream",
+			},
+		},
+		{
+			name: "allowed image of the remote worker stream",
+			cfg: service.StreamLaunchConfig{
+				Mode:  service.StreamLaunchModeRemoteWorker,
+				Image: "codecord/stream:beta",
+			},
+		},
+		{
+			name: "not allowed image",
+			cfg: service.StreamLaunchConfig{
+				Mode:  service.StreamLaunchModeDockerContainer,
+				Image: "attacker/stream",
+			},
+			wantErr: true,
+		},
+		{
+			name: "image of the standalone stream",
+			cfg: service.StreamLaunchConfig{
+				Mode:  service.StreamLaunchModeStandaloneApp,
+				Image: "codecord/stream",
+			},
+			wantErr: true,
+		},
+		{
+			name: "image of the default mode",
+			cfg: service.StreamLaunchConfig{
+				Image: "codecord/stream",
+			},
+			wantErr: true,
+		},
+		{
+			name: "no image of the default mode",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.checkStreamImage(tt.cfg)
+			if tt.wantErr && err == nil {
+				t.Error("stream image is accepted")
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("could not check stream image: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/code-cord/cc.core.server/service"
+	"github.com/sirupsen/logrus"
+)
+
+// KickParticipant removes participant from the stream on behalf of the actor.
+//
+// Access token of the participant is rejected afterwards, but the participant may join
+// the stream again. The actor must have a higher role than the participant.
+func (s *Server) KickParticipant(ctx context.Context, streamUUID, actorUUID, participantUUID string) error {
+	return s.removeParticipant(ctx, streamUUID, actorUUID, participantUUID, false)
+}
+
+// BanParticipant removes participant from the stream on behalf of the actor and blocks it.
+//
+// Access token of the participant is rejected afterwards and participants with the same
+// name or IP can't join the stream anymore. The actor must have a higher role than
+// the participant.
+func (s *Server) BanParticipant(ctx context.Context, streamUUID, actorUUID, participantUUID string) error {
+	return s.removeParticipant(ctx, streamUUID, actorUUID, participantUUID, true)
+}
+
+// Participant returns participant of the stream by UUID.
+//
+// Only joined participants and the current host are returned, os.ErrNotExist
+// is returned otherwise.
+func (s *Server) Participant(ctx context.Context, streamUUID, participantUUID string) (
+	*service.Participant, error) {
+	access, err := s.streamAccess(streamUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	p, ok := access.participants[participantUUID]
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+
+	return &p, nil
+}
+
+func (s *Server) removeParticipant(ctx context.Context,
+	streamUUID, actorUUID, participantUUID string, ban bool) error {
+	var (
+		p       participantInfo
+		removed bool
+	)
+	err := s.updateParticipants(streamUUID, func(participants []participantInfo) (
+		[]participantInfo, error) {
+		actor, err := s.Participant(ctx, streamUUID, actorUUID)
+		if err != nil || actor.Status != service.ParticipantStatusActive {
+			return nil, fmt.Errorf("could not find active participant by UUID %s", actorUUID)
+		}
+
+		idx := -1
+		for i := range participants {
+			if participants[i].UUID == participantUUID {
+				idx = i
+				break
+			}
+		}
+		if idx == -1 {
+			return nil, fmt.Errorf("could not find participant by UUID %s", participantUUID)
+		}
+
+		p = participants[idx]
+		if !outranks(actor.Role, participantRole(&p)) {
+			return nil, fmt.Errorf("%w: %s can't remove %s", service.ErrInsufficientRole,
+				actor.Role, participantRole(&p))
+		}
+
+		if p.Status == service.ParticipantStatusBlocked {
+			if ban {
+				return participants, nil
+			}
+
+			return nil, fmt.Errorf("participant %s is banned", participantUUID)
+		}
+		removed = true
+
+		if ban {
+			p.Status = service.ParticipantStatusBlocked
+			participants[idx] = p
+
+			return participants, nil
+		}
+
+		return append(participants[:idx], participants[idx+1:]...), nil
+	})
+	if err != nil || !removed {
+		return err
+	}
+
+	go s.notifyParticipantRemoved(streamUUID, service.StreamParticipant{
+		UUID:     p.UUID,
+		Name:     p.Name,
+		AvatarID: p.AvatarID,
+		Status:   p.Status,
+		Role:     participantRole(&p),
+	})
+
+	return nil
+}
+
+// checkParticipantBan makes sure the participant isn't banned by name or IP.
+func (s *Server) checkParticipantBan(streamUUID string, p service.Participant) error {
+	participants, err := s.loadParticipants(streamUUID)
+	if err != nil {
+		return err
+	}
+
+	return participantBan(participants, p)
+}
+
+// participantBan makes sure the participant isn't banned by name or IP among the participants.
+func participantBan(participants []participantInfo, p service.Participant) error {
+	for i := range participants {
+		banned := &participants[i]
+		if banned.Status != service.ParticipantStatusBlocked {
+			continue
+		}
+
+		if strings.EqualFold(banned.Name, p.Name) ||
+			(p.IP != "" && participantHost(banned.IP) == participantHost(p.IP)) {
+			return errors.New("participant is banned from the stream")
+		}
+	}
+
+	return nil
+}
+
+// updateParticipants applies the update to the stored participants of the stream served
+// by the server.
+//
+// Updates are serialized per stream along with the stream info updates, so concurrent
+// joins, removals and role changes don't overwrite each other.
+func (s *Server) updateParticipants(streamUUID string,
+	update func(participants []participantInfo) ([]participantInfo, error)) error {
+	unlock := s.lockStreamInfo(streamUUID)
+	defer unlock()
+
+	if _, ok := s.streams.Load(streamUUID); !ok {
+		return fmt.Errorf("could not find running stream by UUID %s", streamUUID)
+	}
+
+	participants, err := s.loadParticipants(streamUUID)
+	if err != nil {
+		return err
+	}
+
+	participants, err = update(participants)
+	if err != nil {
+		return err
+	}
+
+	if err := s.participantStorage.Default().
+		Store(streamUUID, participants, json.Marshal); err != nil {
+		return fmt.Errorf("could not store participants: %v", err)
+	}
+	s.access.invalidate(streamUUID)
+
+	return nil
+}
+
+func (s *Server) loadParticipants(streamUUID string) ([]participantInfo, error) {
+	var participants []participantInfo
+
+	participantRV := s.participantStorage.Default().Load(streamUUID)
+	if participantRV == nil {
+		return participants, nil
+	}
+
+	if err := participantRV.Decode(&participants, json.Unmarshal); err != nil {
+		return nil, fmt.Errorf("could not decode participants data: %v", err)
+	}
+
+	return participants, nil
+}
+
+func (s *Server) notifyParticipantRemoved(streamUUID string, p service.StreamParticipant) {
+	stream, ok := s.streams.Load(streamUUID)
+	if !ok {
+		logrus.Errorf(
+			"could not find running stream by UUID %s to remove participant", streamUUID)
+		return
+	}
+
+	module := stream.(*streamModule)
+	if err := module.streamHandler().RemoveParticipant(p); err != nil {
+		logrus.Errorf("could not remove participant %s from the stream: %v", p.UUID, err)
+	}
+}
+
+// participantHost returns IP of the participant without port.
+func participantHost(ip string) string {
+	// forwarded IP may contain the chain of proxies.
+	ip = strings.TrimSpace(strings.Split(ip, ",")[0])
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		return host
+	}
+
+	return ip
+}
@@ -0,0 +1,212 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/code-cord/cc.core.server/service"
+)
+
+func TestRemoveParticipantRoles(t *testing.T) {
+	s := newTestServer(t)
+	ctx := context.Background()
+
+	stream, err := s.NewStream(ctx, testStreamConfig())
+	if err != nil {
+		t.Fatalf("could not create stream: %v", err)
+	}
+
+	roles := map[string]service.ParticipantRole{
+		"first co-host":  service.ParticipantRoleCoHost,
+		"second co-host": service.ParticipantRoleCoHost,
+		"first editor":   service.ParticipantRoleEditor,
+		"second editor":  service.ParticipantRoleEditor,
+		"viewer":         service.ParticipantRoleViewer,
+	}
+	uuids := map[string]string{
+		"host": stream.Host.UUID,
+	}
+	for name := range roles {
+		_, err := s.JoinParticipant(ctx, stream.UUID, stream.JoinCode, service.Participant{
+			Name: name,
+		})
+		if err != nil {
+			t.Fatalf("could not join %s: %v", name, err)
+		}
+	}
+
+	participants, err := s.StreamParticipants(ctx, stream.UUID)
+	if err != nil {
+		t.Fatalf("could not get participants: %v", err)
+	}
+	for _, p := range participants {
+		uuids[p.Name] = p.UUID
+		if _, err := s.ChangeParticipantRole(ctx, stream.UUID, p.UUID, roles[p.Name]); err != nil {
+			t.Fatalf("could not change role of %s: %v", p.Name, err)
+		}
+	}
+
+	tests := []struct {
+		actor       string
+		participant string
+		ban         bool
+		wantErr     error
+	}{
+		{actor: "second co-host", participant: "first co-host", wantErr: service.ErrInsufficientRole},
+		{actor: "viewer", participant: "first editor", wantErr: service.ErrInsufficientRole},
+		{actor: "first editor", participant: "second editor", wantErr: service.ErrInsufficientRole},
+		{actor: "first co-host", participant: "first editor"},
+		{actor: "host", participant: "second co-host", ban: true},
+		{actor: "second editor", participant: "viewer", ban: true},
+	}
+
+	for _, tt := range tests {
+		var err error
+		if tt.ban {
+			err = s.BanParticipant(ctx, stream.UUID, uuids[tt.actor], uuids[tt.participant])
+		} else {
+			err = s.KickParticipant(ctx, stream.UUID, uuids[tt.actor], uuids[tt.participant])
+		}
+
+		if tt.wantErr != nil {
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("%s removes %s: unexpected error %v", tt.actor, tt.participant, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s could not remove %s: %v", tt.actor, tt.participant, err)
+		}
+	}
+
+	// the host isn't removed by anybody.
+	if err := s.KickParticipant(ctx, stream.UUID, uuids["first co-host"], uuids["host"]); err == nil {
+		t.Error("host is kicked by co-host")
+	}
+
+	// removed participants can't act anymore.
+	err = s.KickParticipant(ctx, stream.UUID, uuids["second co-host"], uuids["second editor"])
+	if err == nil {
+		t.Error("banned co-host kicks participants")
+	}
+}
+
+func TestRemoveParticipantConcurrentJoins(t *testing.T) {
+	s := newTestServer(t)
+	ctx := context.Background()
+
+	stream, err := s.NewStream(ctx, testStreamConfig())
+	if err != nil {
+		t.Fatalf("could not create stream: %v", err)
+	}
+
+	if _, err := s.JoinParticipant(ctx, stream.UUID, stream.JoinCode, service.Participant{
+		Name: "banned",
+	}); err != nil {
+		t.Fatalf("could not join participant: %v", err)
+	}
+	participants, err := s.StreamParticipants(ctx, stream.UUID)
+	if err != nil || len(participants) != 1 {
+		t.Fatalf("could not get participants: %v", err)
+	}
+	bannedUUID := participants[0].UUID
+
+	const joins = 20
+	var wg sync.WaitGroup
+	wg.Add(joins + 1)
+	go func() {
+		defer wg.Done()
+		if err := s.BanParticipant(ctx, stream.UUID, stream.Host.UUID, bannedUUID); err != nil {
+			t.Errorf("could not ban participant: %v", err)
+		}
+	}()
+	for i := 0; i < joins; i++ {
+		go func(i int) {
+			defer wg.Done()
+			_, err := s.JoinParticipant(ctx, stream.UUID, stream.JoinCode, service.Participant{
+				Name: fmt.Sprintf("participant %d", i),
+			})
+			if err != nil {
+				t.Errorf("could not join participant: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	participants, err = s.StreamParticipants(ctx, stream.UUID)
+	if err != nil {
+		t.Fatalf("could not get participants: %v", err)
+	}
+	if len(participants) != joins+1 {
+		t.Errorf("joins are lost: %d participants are stored", len(participants))
+	}
+	for _, p := range participants {
+		if p.UUID == bannedUUID && p.Status != service.ParticipantStatusBlocked {
+			t.Errorf("banned participant is brought back with %s status", p.Status)
+		}
+	}
+}
+
+func TestJoinParticipantBannedWhilePending(t *testing.T) {
+	s := newTestServer(t)
+	ctx := context.Background()
+
+	cfg := testStreamConfig()
+	cfg.Join.JoinPolicy = service.JoinPolicyHostResolve
+	stream, err := s.NewStream(ctx, cfg)
+	if err != nil {
+		t.Fatalf("could not create stream: %v", err)
+	}
+
+	// join approves the pending join request of the participant once it's waiting
+	// for the host.
+	join := func(p service.Participant, beforeApproval func()) error {
+		errs := make(chan error, 1)
+		go func() {
+			_, err := s.JoinParticipant(ctx, stream.UUID, "", p)
+			errs <- err
+		}()
+
+		for {
+			participants, err := s.StreamParticipants(ctx, stream.UUID)
+			if err != nil {
+				t.Fatalf("could not get participants: %v", err)
+			}
+
+			for _, pending := range participants {
+				if pending.Status != service.ParticipantStatusPending {
+					continue
+				}
+
+				beforeApproval()
+				if err := s.DecideParticipantJoin(ctx, stream.UUID, pending.UUID, true); err != nil {
+					t.Fatalf("could not approve join: %v", err)
+				}
+
+				return <-errs
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	if err := join(service.Participant{Name: "first", IP: "203.0.113.5"}, func() {}); err != nil {
+		t.Fatalf("could not join participant: %v", err)
+	}
+
+	participants, err := s.StreamParticipants(ctx, stream.UUID)
+	if err != nil || len(participants) != 1 {
+		t.Fatalf("could not get participants: %v", err)
+	}
+	err = join(service.Participant{Name: "second", IP: "203.0.113.5"}, func() {
+		if err := s.BanParticipant(ctx, stream.UUID, stream.Host.UUID, participants[0].UUID); err != nil {
+			t.Fatalf("could not ban participant: %v", err)
+		}
+	})
+	if err == nil {
+		t.Error("participant banned while waiting for the host is joined")
+	}
+}
@@ -0,0 +1,194 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/code-cord/cc.core.server/service"
+	"github.com/code-cord/cc.core.server/stream"
+)
+
+// launchModeRegistry represents registry of the stream launch modes of the server.
+type launchModeRegistry struct {
+	mu        sync.RWMutex
+	factories map[service.StreamLaunchMode]LaunchModeFactory
+}
+
+// LaunchModeFactory creates a new instance of the stream launched in the specific mode.
+type LaunchModeFactory func(cfg LaunchModeConfig) (service.Stream, error)
+
+// LaunchModeConfig represents configuration model passed to the launch mode factory.
+type LaunchModeConfig struct {
+	StreamUUID string
+	Launch     service.StreamLaunchConfig
+	Options    Options
+	Ports      stream.PortReserver
+}
+
+func newLaunchModeRegistry() *launchModeRegistry {
+	registry := launchModeRegistry{
+		factories: map[service.StreamLaunchMode]LaunchModeFactory{
+			service.StreamLaunchModeStandaloneApp:   newStandaloneStream,
+			service.StreamLaunchModeDockerContainer: newDockerContainerStream,
+			service.StreamLaunchModeRemoteWorker:    newRemoteWorkerStream,
+		},
+	}
+
+	return &registry
+}
+
+// RegisterLaunchMode registers stream launch mode of the server with the factory
+// of its streams.
+//
+// Registered launch mode can be requested by the stream configuration. An error is
+// returned if the name doesn't match service.LaunchModeNameRegexp, the factory is nil
+// or the launch mode has been already registered.
+func (s *Server) RegisterLaunchMode(name service.StreamLaunchMode, factory LaunchModeFactory) error {
+	if name == "" {
+		return errors.New("launch mode name is empty")
+	}
+
+	if !service.LaunchModeNameRegexp.MatchString(string(name)) {
+		return fmt.Errorf("launch mode name %s is invalid", name)
+	}
+
+	if factory == nil {
+		return fmt.Errorf("launch mode %s factory is nil", name)
+	}
+
+	s.launchModes.mu.Lock()
+	defer s.launchModes.mu.Unlock()
+
+	if _, ok := s.launchModes.factories[name]; ok {
+		return fmt.Errorf("launch mode %s is already registered", name)
+	}
+	s.launchModes.factories[name] = factory
+
+	return nil
+}
+
+// LaunchModes returns sorted list of the registered stream launch modes.
+func (s *Server) LaunchModes() []service.StreamLaunchMode {
+	s.launchModes.mu.RLock()
+	defer s.launchModes.mu.RUnlock()
+
+	modes := make([]service.StreamLaunchMode, 0, len(s.launchModes.factories))
+	for mode := range s.launchModes.factories {
+		modes = append(modes, mode)
+	}
+
+	sort.Slice(modes, func(i, j int) bool {
+		return modes[i] < modes[j]
+	})
+
+	return modes
+}
+
+func (s *Server) launchModeFactory(mode service.StreamLaunchMode) (LaunchModeFactory, error) {
+	s.launchModes.mu.RLock()
+	defer s.launchModes.mu.RUnlock()
+
+	factory, ok := s.launchModes.factories[mode]
+	if !ok {
+		return nil, fmt.Errorf("%w: %v", service.ErrInvalidLaunchMode, mode)
+	}
+
+	return factory, nil
+}
+
+// checkLaunchMode makes sure the stream can be launched in the mode without creating
+// the stream instance.
+func (s *Server) checkLaunchMode(cfg service.StreamLaunchConfig) error {
+	if _, err := s.launchModeFactory(cfg.Mode); err != nil {
+		return err
+	}
+
+	if cfg.Mode == service.StreamLaunchModeRemoteWorker && len(s.opts.Workers) == 0 {
+		return errors.New("no remote workers are configured")
+	}
+
+	return nil
+}
+
+func (s *Server) newStreamHandler(cfg service.StreamLaunchConfig, streamUUID string) (
+	service.Stream, error) {
+	factory, err := s.launchModeFactory(cfg.Mode)
+	if err != nil {
+		return nil, err
+	}
+
+	stream, err := factory(LaunchModeConfig{
+		StreamUUID: streamUUID,
+		Launch:     cfg,
+		Options:    s.opts,
+		Ports: &streamPortReserver{
+			pool:       s.ports,
+			streamUUID: streamUUID,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not create %s stream: %v", cfg.Mode, err)
+	}
+
+	return stream, nil
+}
+
+// InProcessLaunchMode returns factory of the streams served by the handler within the
+// server process.
+//
+// The in-process launch mode isn't registered by default, the embedder has to register
+// it with the handler implementing the stream API:
+//
+//	server.New(server.LaunchMode(service.StreamLaunchModeInProcess, server.InProcessLaunchMode(h)))
+func InProcessLaunchMode(handler http.Handler) LaunchModeFactory {
+	return func(cfg LaunchModeConfig) (service.Stream, error) {
+		return stream.NewInProcessStream(stream.InProcessStreamConfig{
+			PreferedIP:   cfg.Launch.PreferredIP,
+			PreferedPort: cfg.Launch.PreferredPort,
+			Handler:      handler,
+			StopTimeout:  cfg.Options.StreamStopTimeout,
+		}), nil
+	}
+}
+
+func newStandaloneStream(cfg LaunchModeConfig) (service.Stream, error) {
+	return stream.NewStandaloneStream(stream.StandaloneStreamConfig{
+		PreferedIP:   cfg.Launch.PreferredIP,
+		PreferedPort: cfg.Launch.PreferredPort,
+		BinPath:      cfg.Options.BinFolder,
+		StopTimeout:  cfg.Options.StreamStopTimeout,
+		PIDFile:      streamPIDFile(cfg.Options.DataFolder, cfg.StreamUUID),
+		Limits:       cfg.Launch.Limits,
+		CgroupParent: cfg.Options.StreamCgroupParent,
+		Ports:        cfg.Ports,
+		Env:          cfg.Launch.Env,
+		Args:         cfg.Launch.Args,
+	}), nil
+}
+
+func newDockerContainerStream(cfg LaunchModeConfig) (service.Stream, error) {
+	return stream.NewDockerContainerStream(stream.DockerContainerStreamConfig{
+		StreamUUID:      cfg.StreamUUID,
+		ContainerPrefix: cfg.Options.StreamContainerPrefix,
+		DockerImage:     streamImage(cfg.Launch.Image, cfg.Options.StreamImage),
+		PreferedPort:    cfg.Launch.PreferredPort,
+		PreferedIP:      cfg.Launch.PreferredIP,
+		StopTimeout:     cfg.Options.StreamStopTimeout,
+		Limits:          cfg.Launch.Limits,
+		Ports:           cfg.Ports,
+		Env:             cfg.Launch.Env,
+		Args:            cfg.Launch.Args,
+	}), nil
+}
+
+// streamImage returns docker image of the stream falling back to the default one.
+func streamImage(image, defaultImage string) string {
+	if image == "" {
+		return defaultImage
+	}
+
+	return image
+}
@@ -0,0 +1,228 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/code-cord/cc.core.server/handler/models"
+	"github.com/code-cord/cc.core.server/service"
+)
+
+func TestRegisterLaunchMode(t *testing.T) {
+	s, err := New(DataFolder(t.TempDir()))
+	if err != nil {
+		t.Fatalf("could not init server: %v", err)
+	}
+	defer s.Stop(context.Background())
+
+	factory := InProcessLaunchMode(http.NotFoundHandler())
+	if err := s.RegisterLaunchMode(service.StreamLaunchModeInProcess, factory); err != nil {
+		t.Fatalf("could not register launch mode: %v", err)
+	}
+	if err := s.RegisterLaunchMode(service.StreamLaunchModeInProcess, factory); err == nil {
+		t.Error("duplicated launch mode is registered")
+	}
+	if err := s.RegisterLaunchMode(service.StreamLaunchModeStandaloneApp, factory); err == nil {
+		t.Error("default launch mode is overridden")
+	}
+	if err := s.RegisterLaunchMode("custom", nil); err == nil {
+		t.Error("launch mode without factory is registered")
+	}
+	for _, name := range []service.StreamLaunchMode{"my-mode", "MyMode", "1mode"} {
+		if err := s.RegisterLaunchMode(name, factory); err == nil {
+			t.Errorf("launch mode with invalid name %s is registered", name)
+		}
+	}
+
+	if _, err := New(
+		DataFolder(t.TempDir()),
+		LaunchMode("custom", factory),
+		LaunchMode("custom", factory),
+	); err == nil {
+		t.Error("duplicated launch mode option is accepted")
+	}
+
+	other, err := New(DataFolder(t.TempDir()))
+	if err != nil {
+		t.Fatalf("could not init server: %v", err)
+	}
+	defer other.Stop(context.Background())
+
+	for _, mode := range other.LaunchModes() {
+		if mode == service.StreamLaunchModeInProcess {
+			t.Error("launch mode is shared between servers")
+		}
+	}
+
+	err = other.checkLaunchMode(service.StreamLaunchConfig{
+		Mode: service.StreamLaunchModeInProcess,
+	})
+	if err == nil {
+		t.Error("unregistered launch mode is accepted")
+	}
+}
+
+func TestInProcessStreamFlow(t *testing.T) {
+	streamHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "%s %s", r.Method, r.URL.Path)
+	})
+
+	s, err := New(
+		DataFolder(t.TempDir()),
+		StreamStopTimeout(time.Second),
+		LaunchMode(service.StreamLaunchModeInProcess, InProcessLaunchMode(streamHandler)),
+	)
+	if err != nil {
+		t.Fatalf("could not init server: %v", err)
+	}
+	defer s.Stop(context.Background())
+
+	srv := httptest.NewServer(s.httpServer.Handler)
+	defer srv.Close()
+
+	// create stream.
+	var stream models.StreamOwnerInfoResponse
+	status := doJSON(t, http.MethodPost, srv.URL+"/stream", "", models.CreateStreamRequest{
+		Name: "in-process stream",
+		Join: models.JoinPolicyRequest{
+			Policy: service.JoinPolicyAuto,
+		},
+		Stream: models.StreamConfigRequest{
+			LaunchMode: service.StreamLaunchModeInProcess,
+		},
+		Host: models.StreamHostInfoRequest{
+			Name: "stream host",
+		},
+	}, &stream)
+	if status != http.StatusCreated && status != http.StatusOK {
+		t.Fatalf("could not create stream: status %d", status)
+	}
+	if stream.LaunchMode != service.StreamLaunchModeInProcess {
+		t.Fatalf("unexpected launch mode: %s", stream.LaunchMode)
+	}
+	if stream.Auth == nil {
+		t.Fatal("host token is not issued")
+	}
+
+	// join stream.
+	var join models.ParticipantJoinResponse
+	status = doJSON(t, http.MethodPost, srv.URL+"/stream/"+stream.UUID+"/join", "",
+		models.ParticipantJoinRequest{
+			Name: "participant",
+		}, &join)
+	if status != http.StatusOK || !join.Allowed {
+		t.Fatalf("participant is not joined: status %d, join status %s", status, join.Status)
+	}
+
+	// proxy participant requests to the stream handler, the stream address isn't
+	// handed out to the participants.
+	proxy := func(method string) (int, string) {
+		req, err := http.NewRequest(method, srv.URL+"/stream/"+stream.UUID+"/service/files/main", nil)
+		if err != nil {
+			t.Fatalf("could not create proxy request: %v", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+join.AccessToken)
+
+		resp, err := http.DefaultTransport.RoundTrip(req)
+		if err != nil {
+			t.Fatalf("could not send proxy request: %v", err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		return resp.StatusCode, string(body)
+	}
+	for _, method := range []string{http.MethodGet, http.MethodPost} {
+		status, body := proxy(method)
+		if status != http.StatusOK {
+			t.Fatalf("unexpected %s proxy status: %d", method, status)
+		}
+		if want := method + " /files/main"; body != want {
+			t.Errorf("unexpected proxy response: got %q, want %q", body, want)
+		}
+	}
+
+	// viewers have read-only access to the stream handler.
+	participants, err := s.StreamParticipants(context.Background(), stream.UUID)
+	if err != nil || len(participants) != 1 {
+		t.Fatalf("could not get participants: %v", err)
+	}
+	if _, err := s.ChangeParticipantRole(context.Background(), stream.UUID, participants[0].UUID,
+		service.ParticipantRoleViewer); err != nil {
+		t.Fatalf("could not change participant role: %v", err)
+	}
+	if status, _ := proxy(http.MethodGet); status != http.StatusOK {
+		t.Errorf("unexpected viewer GET proxy status: %d", status)
+	}
+	if status, _ := proxy(http.MethodPost); status != http.StatusForbidden &&
+		status != http.StatusMethodNotAllowed {
+		t.Errorf("unexpected viewer POST proxy status: %d", status)
+	}
+
+	// participant isn't allowed to finish the stream.
+	status = doJSON(t, http.MethodDelete, srv.URL+"/stream/"+stream.UUID, join.AccessToken, nil, nil)
+	if status == http.StatusOK {
+		t.Fatal("stream is finished by participant")
+	}
+
+	// finish stream.
+	status = doJSON(t, http.MethodDelete, srv.URL+"/stream/"+stream.UUID, stream.Auth.AccessToken, nil, nil)
+	if status != http.StatusOK {
+		t.Fatalf("could not finish stream: status %d", status)
+	}
+
+	var info models.StreamPublicInfoResponse
+	status = doJSON(t, http.MethodGet, srv.URL+"/stream/"+stream.UUID, "", nil, &info)
+	if status != http.StatusOK {
+		t.Fatalf("could not get stream info: status %d", status)
+	}
+	if info.Status != service.StreamStatusFinished {
+		t.Errorf("unexpected stream status: %s", info.Status)
+	}
+
+	if _, err := http.Get(fmt.Sprintf("http://%s:%d/", stream.IP, stream.Port)); err == nil {
+		t.Error("stream handler is still served after the stream is finished")
+	}
+}
+
+func doJSON(t *testing.T, method, url, token string, in, out interface{}) int {
+	t.Helper()
+
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			t.Fatalf("could not encode request: %v", err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		t.Fatalf("could not create request: %v", err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("could not send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if out != nil && resp.StatusCode < http.StatusMultipleChoices {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			t.Fatalf("could not decode response: %v", err)
+		}
+	}
+
+	return resp.StatusCode
+}
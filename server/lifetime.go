@@ -0,0 +1,197 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/code-cord/cc.core.server/service"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultLifetimeCheckInterval = 30 * time.Second
+	defaultStreamFinishWarning   = 5 * time.Minute
+)
+
+// streamLifetime represents stream lifetime state model.
+type streamLifetime struct {
+	startedAt        time.Time
+	maxDuration      time.Duration
+	idleTimeout      time.Duration
+	lastTraffic      time.Time
+	lastParticipants time.Time
+	warnedDeadline   time.Time
+}
+
+// runLifetimeChecks periodically finishes streams which have exceeded their max
+// duration or have been idle for too long until the server is stopped.
+func (s *Server) runLifetimeChecks() {
+	ticker := time.NewTicker(defaultLifetimeCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.checkStreamsLifetime()
+		case <-s.done:
+			return
+		}
+	}
+}
+
+func (s *Server) checkStreamsLifetime() {
+	var wg sync.WaitGroup
+	s.streams.Range(func(key, value interface{}) bool {
+		wg.Add(1)
+		go func(streamUUID string, module *streamModule) {
+			defer wg.Done()
+
+			s.checkStreamLifetime(streamUUID, module)
+		}(key.(string), value.(*streamModule))
+
+		return true
+	})
+	wg.Wait()
+}
+
+func (s *Server) checkStreamLifetime(streamUUID string, module *streamModule) {
+	now := time.Now().UTC()
+	if s.hasActiveParticipants(streamUUID) {
+		module.touchParticipants(now)
+	}
+
+	deadline, reason := module.finishDeadline()
+	if deadline.IsZero() {
+		return
+	}
+
+	if !now.Before(deadline) {
+		logrus.Infof("finishing %s stream: %s", streamUUID, reason)
+		s.killStream(context.Background(), streamUUID)
+		return
+	}
+
+	// paused stream can't receive the warning.
+	warning := s.opts.StreamFinishWarning
+	if deadline.Sub(now) > warning || module.isPaused() || !module.markWarned(deadline) {
+		return
+	}
+
+	// the sweep doesn't wait for the stream to receive the warning.
+	go func(handler service.StreamHandler) {
+		err := handler.SendEvent(service.StreamEvent{
+			Type: service.StreamEventFinishWarning,
+			Payload: service.StreamFinishWarning{
+				Reason:   reason,
+				FinishAt: deadline,
+			},
+		})
+		if err != nil {
+			logrus.Errorf("could not send finish warning to the %s stream: %v", streamUUID, err)
+		}
+	}(module.streamHandler())
+}
+
+// hasActiveParticipants reports whether the stream has active participants other than the host.
+func (s *Server) hasActiveParticipants(streamUUID string) bool {
+	participantRV := s.participantStorage.Default().Load(streamUUID)
+	if participantRV == nil {
+		return false
+	}
+
+	var participants []participantInfo
+	if err := participantRV.Decode(&participants, json.Unmarshal); err != nil {
+		logrus.Errorf("could not decode %s stream participants data: %v", streamUUID, err)
+		return false
+	}
+
+	for i := range participants {
+		if participants[i].Status == service.ParticipantStatusActive {
+			return true
+		}
+	}
+
+	return false
+}
+
+func newStreamLifetime(
+	startedAt time.Time, maxDuration, idleTimeout time.Duration) streamLifetime {
+	now := time.Now().UTC()
+
+	return streamLifetime{
+		startedAt:        startedAt,
+		maxDuration:      maxDuration,
+		idleTimeout:      idleTimeout,
+		lastTraffic:      now,
+		lastParticipants: now,
+	}
+}
+
+// touchTraffic records proxied traffic of the stream.
+func (m *streamModule) touchTraffic(now time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.lifetime.lastTraffic = now
+}
+
+// touchParticipants records presence of active participants in the stream.
+func (m *streamModule) touchParticipants(now time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.lifetime.lastParticipants = now
+}
+
+// finishDeadline returns the nearest time when the stream has to be finished along with
+// the reason of finishing. Zero time is returned if the stream has no deadline.
+//
+// The stream is idle if it has no active participants other than the host or no
+// proxied traffic.
+func (m *streamModule) finishDeadline() (time.Time, service.StreamFinishReason) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var (
+		deadline time.Time
+		reason   service.StreamFinishReason
+	)
+
+	lifetime := &m.lifetime
+	if lifetime.maxDuration > 0 {
+		deadline = lifetime.startedAt.Add(lifetime.maxDuration)
+		reason = service.StreamFinishReasonMaxDuration
+	}
+
+	if lifetime.idleTimeout > 0 {
+		idleSince := lifetime.lastTraffic
+		if lifetime.lastParticipants.Before(idleSince) {
+			idleSince = lifetime.lastParticipants
+		}
+
+		idleDeadline := idleSince.Add(lifetime.idleTimeout)
+		if deadline.IsZero() || idleDeadline.Before(deadline) {
+			deadline = idleDeadline
+			reason = service.StreamFinishReasonIdle
+		}
+	}
+
+	return deadline, reason
+}
+
+// markWarned marks the stream as warned about the deadline.
+//
+// It reports whether the stream hasn't been warned about this deadline yet.
+func (m *streamModule) markWarned(deadline time.Time) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.lifetime.warnedDeadline.Equal(deadline) {
+		return false
+	}
+	m.lifetime.warnedDeadline = deadline
+
+	return true
+}
@@ -0,0 +1,66 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/code-cord/cc.core.server/service"
+)
+
+func TestCheckStreamsLifetimeWithHungStream(t *testing.T) {
+	// the stream never answers the events.
+	hung := make(chan struct{})
+	defer close(hung)
+	streamHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/event" {
+			<-hung
+		}
+	})
+
+	s, err := New(
+		DataFolder(t.TempDir()),
+		StreamStopTimeout(time.Second),
+		StreamFinishWarning(2*time.Hour),
+		LaunchMode(service.StreamLaunchModeInProcess, InProcessLaunchMode(streamHandler)),
+	)
+	if err != nil {
+		t.Fatalf("could not init server: %v", err)
+	}
+	defer s.Stop(context.Background())
+	ctx := context.Background()
+
+	cfg := testStreamConfig()
+	cfg.MaxDuration = time.Hour
+	warned, err := s.NewStream(ctx, cfg)
+	if err != nil {
+		t.Fatalf("could not create stream: %v", err)
+	}
+
+	cfg.MaxDuration = time.Millisecond
+	expired, err := s.NewStream(ctx, cfg)
+	if err != nil {
+		t.Fatalf("could not create stream: %v", err)
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	checked := make(chan struct{})
+	go func() {
+		defer close(checked)
+		s.checkStreamsLifetime()
+	}()
+
+	select {
+	case <-checked:
+	case <-time.After(time.Second):
+		t.Fatal("lifetime check waits for the finish warning")
+	}
+
+	if _, ok := s.streams.Load(expired.UUID); ok {
+		t.Error("expired stream isn't finished")
+	}
+	if _, ok := s.streams.Load(warned.UUID); !ok {
+		t.Error("warned stream is finished")
+	}
+}
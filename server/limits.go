@@ -0,0 +1,56 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/code-cord/cc.core.server/service"
+)
+
+const (
+	defaultStreamCgroupParent = "code-cord"
+)
+
+type streamLimitsInfo struct {
+	CPU    float64 `json:"cpu,omitempty"`
+	Memory int64   `json:"memory,omitempty"`
+	PIDs   int64   `json:"pids,omitempty"`
+}
+
+// streamResourceLimits returns resource limits of the new stream.
+//
+// Server-wide limits are used by default and can only be lowered by the stream.
+func (s *Server) streamResourceLimits(limits service.StreamResourceLimits) (
+	service.StreamResourceLimits, error) {
+	if limits.CPU <= 0 {
+		limits.CPU = s.opts.StreamCPULimit
+	}
+	if limits.Memory <= 0 {
+		limits.Memory = s.opts.StreamMemoryLimit
+	}
+	if limits.PIDs <= 0 {
+		limits.PIDs = s.opts.StreamPIDsLimit
+	}
+
+	if err := checkResourceLimit("CPU", limits.CPU, s.opts.StreamCPULimit); err != nil {
+		return limits, err
+	}
+	err := checkResourceLimit("memory", float64(limits.Memory), float64(s.opts.StreamMemoryLimit))
+	if err != nil {
+		return limits, err
+	}
+	err = checkResourceLimit("PIDs", float64(limits.PIDs), float64(s.opts.StreamPIDsLimit))
+	if err != nil {
+		return limits, err
+	}
+
+	return limits, nil
+}
+
+func checkResourceLimit(name string, streamLimit, serverLimit float64) error {
+	if serverLimit > 0 && streamLimit > serverLimit {
+		return fmt.Errorf("stream %s limit %v exceeds server limit %v",
+			name, streamLimit, serverLimit)
+	}
+
+	return nil
+}
@@ -2,7 +2,11 @@ package server
 
 import (
 	"crypto/rsa"
+	"net"
+	"net/http"
+	"time"
 
+	"github.com/code-cord/cc.core.server/service"
 	"github.com/sirupsen/logrus"
 )
 
@@ -29,11 +33,46 @@ type Options struct {
 	ServerSecurityPrivateKeyPath string
 	ServerSecurityEnabled        bool
 	BinFolder                    string
+	MasterKeyPath                string
+	MasterKeySecret              string
+	HealthCheckInterval          time.Duration
+	HealthCheckRoute             string
+	UnhealthyThreshold           time.Duration
+	StreamStopTimeout            time.Duration
+	StreamLogMaxSize             int64
+	StreamCPULimit               float64
+	StreamMemoryLimit            int64
+	StreamPIDsLimit              int64
+	StreamCgroupParent           string
+	StreamFinishWarning          time.Duration
+	Workers                      []string
+	WorkerToken                  string
+	WorkerTLSEnabled             bool
+	WorkerCACertFile             string
+	StreamPortRange              string
+	AllowedStreamImages          []string
+	MaxStreams                   int
+	MaxSubjectStreams            int
+	MaxSubjectStreamTime         time.Duration
+	QuotaPeriod                  time.Duration
+	StreamAccessTokenTTL         time.Duration
+	StreamRefreshTokenTTL        time.Duration
+	JoinApprovalTimeout          time.Duration
+	StreamQueueTTL               time.Duration
+	TrustedProxies               []string
+	LaunchModes                  map[service.StreamLaunchMode]LaunchModeFactory
 
 	logLevel   logrus.Level
 	publicKey  *rsa.PublicKey
 	privateKey *rsa.PrivateKey
 	tlsEnabled bool
+	masterKey  []byte
+	portMin    int
+	portMax    int
+
+	trustedProxies       []*net.IPNet
+	workerHTTPClient     *http.Client
+	duplicateLaunchModes []service.StreamLaunchMode
 }
 
 // Name sets server name option.
@@ -164,3 +203,202 @@ func StreamImageRegistryAuth(auth string) Option {
 		o.StreamImageRegistryAuth = auth
 	}
 }
+
+// MasterKey sets path to the server master key file used to encrypt stream access keys.
+//
+// If the file doesn't exist, a new random key will be generated and stored at this path.
+// The master key protects the stream access keys stored in the data folder, so anyone who
+// can read both the key and the data folder can issue stream tokens. Keep the key outside
+// of the data folder or use MasterKeySecret in production.
+func MasterKey(keyPath string) Option {
+	return func(o *Options) {
+		o.MasterKeyPath = keyPath
+	}
+}
+
+// MasterKeySecret sets server master key used to encrypt stream access keys.
+//
+// The secret takes precedence over the master key file.
+func MasterKeySecret(secret string) Option {
+	return func(o *Options) {
+		o.MasterKeySecret = secret
+	}
+}
+
+// HealthCheckInterval sets interval between stream health checks.
+//
+// Negative value disables health checks.
+func HealthCheckInterval(interval time.Duration) Option {
+	return func(o *Options) {
+		o.HealthCheckInterval = interval
+	}
+}
+
+// HealthCheckRoute sets optional HTTP route of the stream to check its health.
+//
+// If route is empty, only TCP connection to the stream is checked.
+func HealthCheckRoute(route string) Option {
+	return func(o *Options) {
+		o.HealthCheckRoute = route
+	}
+}
+
+// UnhealthyThreshold sets duration after which constantly unreachable stream will be finished.
+//
+// Zero value disables finishing of unhealthy streams.
+func UnhealthyThreshold(threshold time.Duration) Option {
+	return func(o *Options) {
+		o.UnhealthyThreshold = threshold
+	}
+}
+
+// StreamStopTimeout sets grace period for the stream to exit after being asked to stop.
+//
+// Once the period is over the stream is killed.
+func StreamStopTimeout(timeout time.Duration) Option {
+	return func(o *Options) {
+		o.StreamStopTimeout = timeout
+	}
+}
+
+// StreamLogMaxSize sets max size of the stream log file in bytes before it's rotated.
+func StreamLogMaxSize(size int64) Option {
+	return func(o *Options) {
+		o.StreamLogMaxSize = size
+	}
+}
+
+// StreamResourceLimits sets server-wide resource limits of the stream instances.
+//
+// cpu is a number of CPUs, memory is a number of bytes and pids is a max number
+// of processes. Zero value means no limit. Streams can only lower these limits.
+func StreamResourceLimits(cpu float64, memory, pids int64) Option {
+	return func(o *Options) {
+		o.StreamCPULimit = cpu
+		o.StreamMemoryLimit = memory
+		o.StreamPIDsLimit = pids
+	}
+}
+
+// StreamCgroupParent sets parent cgroup of the standalone stream instances
+// relative to the cgroup v2 root.
+func StreamCgroupParent(parent string) Option {
+	return func(o *Options) {
+		o.StreamCgroupParent = parent
+	}
+}
+
+// StreamFinishWarning sets how long before finishing the stream due to its max
+// duration or idle timeout the stream is warned.
+func StreamFinishWarning(warning time.Duration) Option {
+	return func(o *Options) {
+		o.StreamFinishWarning = warning
+	}
+}
+
+// RemoteWorkers sets addresses of the workers running remote worker streams along with
+// the token shared with them.
+func RemoteWorkers(token string, addresses ...string) Option {
+	return func(o *Options) {
+		o.WorkerToken = token
+		o.Workers = addresses
+	}
+}
+
+// RemoteWorkersTLS sets whether the server requests API of the remote workers over https.
+//
+// Certificates of the workers are verified with the CA certificate if it's set,
+// otherwise the system roots are used.
+func RemoteWorkersTLS(enabled bool, caCertFile string) Option {
+	return func(o *Options) {
+		o.WorkerTLSEnabled = enabled
+		o.WorkerCACertFile = caCertFile
+	}
+}
+
+// StreamPortRange sets range of the ports in "min-max" format the streams are bound to
+// unless the stream prefers a specific port.
+//
+// If the range is empty, ports are assigned by the system.
+func StreamPortRange(portRange string) Option {
+	return func(o *Options) {
+		o.StreamPortRange = portRange
+	}
+}
+
+// AllowedStreamImages sets docker images the streams may request in addition to
+// the default stream image.
+func AllowedStreamImages(images ...string) Option {
+	return func(o *Options) {
+		o.AllowedStreamImages = images
+	}
+}
+
+// StreamQuota sets max number of the running streams, max number of the running streams
+// of the token subject and max stream time of the subject within the period.
+//
+// Zero value means no limit. Default period is 30 days.
+func StreamQuota(maxStreams, maxSubjectStreams int, maxSubjectStreamTime, period time.Duration) Option {
+	return func(o *Options) {
+		o.MaxStreams = maxStreams
+		o.MaxSubjectStreams = maxSubjectStreams
+		o.MaxSubjectStreamTime = maxSubjectStreamTime
+		o.QuotaPeriod = period
+	}
+}
+
+// JoinApprovalTimeout sets how long join requests of the streams joined with the host
+// approval wait for the decision of the host.
+//
+// Default timeout is 5 minutes.
+func JoinApprovalTimeout(timeout time.Duration) Option {
+	return func(o *Options) {
+		o.JoinApprovalTimeout = timeout
+	}
+}
+
+// TrustedProxies sets IP addresses and CIDR ranges of the proxies allowed to forward
+// IP addresses of the clients with X-Forwarded-For header.
+//
+// The header is ignored by default.
+func TrustedProxies(proxies ...string) Option {
+	return func(o *Options) {
+		o.TrustedProxies = proxies
+	}
+}
+
+// StreamQueueTTL sets how long the queued streams wait for the stream quotas before
+// they are failed.
+//
+// Default TTL is 1 hour.
+func StreamQueueTTL(ttl time.Duration) Option {
+	return func(o *Options) {
+		o.StreamQueueTTL = ttl
+	}
+}
+
+// LaunchMode registers additional stream launch mode of the server along with
+// the factory of its streams.
+//
+// The server isn't created if the same launch mode is passed more than once.
+func LaunchMode(name service.StreamLaunchMode, factory LaunchModeFactory) Option {
+	return func(o *Options) {
+		if o.LaunchModes == nil {
+			o.LaunchModes = make(map[service.StreamLaunchMode]LaunchModeFactory)
+		}
+		if _, ok := o.LaunchModes[name]; ok {
+			o.duplicateLaunchModes = append(o.duplicateLaunchModes, name)
+		}
+		o.LaunchModes[name] = factory
+	}
+}
+
+// StreamTokenTTL sets lifetime of the stream access and refresh tokens.
+//
+// Default lifetime is 15 minutes for the access tokens and 24 hours for the refresh tokens.
+func StreamTokenTTL(accessTTL, refreshTTL time.Duration) Option {
+	return func(o *Options) {
+		o.StreamAccessTokenTTL = accessTTL
+		o.StreamRefreshTokenTTL = refreshTTL
+	}
+}
@@ -5,44 +5,58 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/code-cord/cc.core.server/service"
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 )
 
+const (
+	defaultJoinApprovalTimeout = 5 * time.Minute
+)
+
 type participantInfo struct {
 	UUID        string                    `json:"uuid"`
 	Name        string                    `json:"name"`
 	AvatarID    string                    `json:"avatar,omitempty"`
 	IP          string                    `json:"ip"`
 	Status      service.ParticipantStatus `json:"status"`
+	Role        service.ParticipantRole   `json:"role,omitempty"`
 	pendingChan chan bool
 }
 
 // JoinParticipant joins a new particiant to the stream.
+//
+// If the stream is joined with the host approval, the join request waits for the decision
+// of the host until the approval timeout, the context is done or the stream is finished.
 func (s *Server) JoinParticipant(
-	joinCodectx context.Context, streamUUID, joinCode string, p service.Participant) (
+	ctx context.Context, streamUUID, joinCode string, p service.Participant) (
 	*service.JoinParticipantDecision, error) {
 	streamRV := s.streamStorage.Default().Load(streamUUID)
 	streamValue, ok := s.streams.Load(streamUUID)
 	if !ok || streamRV == nil {
 		return nil, fmt.Errorf("could not find running stream by UUID %s", streamUUID)
 	}
-	streamData := streamValue.(streamModule)
+	streamData := streamValue.(*streamModule)
 
 	var stream streamInfo
 	if err := streamRV.Decode(&stream, json.Unmarshal); err != nil {
 		return nil, fmt.Errorf("could not decode stream data: %v", err)
 	}
 
+	if err := s.checkParticipantBan(streamUUID, p); err != nil {
+		return nil, err
+	}
+
 	pInfo := participantInfo{
 		UUID:        uuid.New().String(),
 		Name:        p.Name,
 		AvatarID:    p.AvatarID,
 		IP:          p.IP,
 		Status:      service.ParticipantStatusPending,
-		pendingChan: make(chan bool),
+		Role:        defaultParticipantRole,
+		pendingChan: make(chan bool, 1),
 	}
 	streamData.pendingParticipants.Store(pInfo.UUID, pInfo)
 	defer streamData.pendingParticipants.Delete(pInfo.UUID)
@@ -57,7 +71,21 @@ func (s *Server) JoinParticipant(
 		}
 		joinDesicion.JoinAllowed = true
 	case service.JoinPolicyHostResolve:
-		joinDesicion.JoinAllowed = <-pInfo.pendingChan
+		timer := time.NewTimer(s.opts.JoinApprovalTimeout)
+		defer timer.Stop()
+
+		select {
+		case joinDesicion.JoinAllowed = <-pInfo.pendingChan:
+			if !joinDesicion.JoinAllowed {
+				joinDesicion.Status = service.JoinStatusRejected
+			}
+		case <-timer.C:
+			joinDesicion.Status = service.JoinStatusExpired
+		case <-streamData.done:
+			return nil, errors.New("stream is finished")
+		case <-ctx.Done():
+			return nil, fmt.Errorf("join request is canceled: %v", ctx.Err())
+		}
 	default:
 		return nil, errors.New("unknown stream join policy")
 	}
@@ -65,25 +93,33 @@ func (s *Server) JoinParticipant(
 	if !joinDesicion.JoinAllowed {
 		return joinDesicion, nil
 	}
+	joinDesicion.Status = service.JoinStatusAllowed
+
+	keys, err := s.streamKeys(streamUUID)
+	if err != nil {
+		return nil, err
+	}
 
-	accessToken, err := generateStreamAccessToken(
-		streamUUID, pInfo.UUID, false, streamData.rsaKeys.privateKey)
+	token, err := s.generateStreamTokens(streamUUID, pInfo.UUID, pInfo.Role, keys.privateKey)
 	if err != nil {
 		return nil, fmt.Errorf("could not generate access token: %v", err)
 	}
 
-	joinDesicion.AccessToken = accessToken
+	joinDesicion.AccessToken = token.AccessToken
+	joinDesicion.RefreshToken = token.RefreshToken
+	joinDesicion.ExpiresAt = token.ExpiresAt
 	pInfo.Status = service.ParticipantStatusActive
 
-	var participants []participantInfo
-	if pRV := s.participantStorage.Default().Load(streamUUID); pRV != nil {
-		if err := pRV.Decode(&participants, json.Unmarshal); err != nil {
-			return nil, fmt.Errorf("could not decode stream participants data: %v", err)
+	// the participant may have been banned while the join request was waiting for the host.
+	err = s.updateParticipants(streamUUID, func(participants []participantInfo) (
+		[]participantInfo, error) {
+		if err := participantBan(participants, p); err != nil {
+			return nil, err
 		}
-	}
-	participants = append(participants, pInfo)
-	if err := s.participantStorage.Default().
-		Store(streamUUID, participants, json.Marshal); err != nil {
+
+		return append(participants, pInfo), nil
+	})
+	if err != nil {
 		return nil, fmt.Errorf("could not add participant: %v", err)
 	}
 
@@ -92,6 +128,7 @@ func (s *Server) JoinParticipant(
 		Name:     pInfo.Name,
 		AvatarID: pInfo.AvatarID,
 		Status:   pInfo.Status,
+		Role:     pInfo.Role,
 		Host:     false,
 	})
 
@@ -99,20 +136,27 @@ func (s *Server) JoinParticipant(
 }
 
 // DecideParticipantJoin allows or denies participant to join the stream.
+//
+// It doesn't wait for the join request to handle the decision, only the first decision
+// on the join request counts.
 func (s *Server) DecideParticipantJoin(
 	ctx context.Context, streamUUID, participantUUID string, joinAllowed bool) error {
 	streamValue, ok := s.streams.Load(streamUUID)
 	if !ok {
 		return fmt.Errorf("could not find running stream by UUID %s", streamUUID)
 	}
-	streamData := streamValue.(streamModule)
+	streamData := streamValue.(*streamModule)
 
-	participantValue, ok := streamData.pendingParticipants.Load(participantUUID)
+	participantValue, ok := streamData.pendingParticipants.LoadAndDelete(participantUUID)
 	if !ok {
 		return fmt.Errorf("could not find pending participant by UUID %s", participantUUID)
 	}
-	participantChan := participantValue.(participantInfo).pendingChan
-	participantChan <- joinAllowed
+
+	select {
+	case participantValue.(participantInfo).pendingChan <- joinAllowed:
+	default:
+		return fmt.Errorf("join request of %s participant is already decided", participantUUID)
+	}
 
 	return nil
 }
@@ -124,7 +168,7 @@ func (s *Server) StreamParticipants(ctx context.Context, streamUUID string) (
 	if !ok {
 		return nil, fmt.Errorf("could not find running stream by UUID %s", streamUUID)
 	}
-	streamData := streamValue.(streamModule)
+	streamData := streamValue.(*streamModule)
 
 	var participants []participantInfo
 	streamData.pendingParticipants.Range(func(key, value interface{}) bool {
@@ -152,6 +196,7 @@ func (s *Server) StreamParticipants(ctx context.Context, streamUUID string) (
 			AvatarID: p.AvatarID,
 			IP:       p.IP,
 			Status:   p.Status,
+			Role:     participantRole(p),
 		}
 	}
 
@@ -199,6 +244,7 @@ func (s *Server) PatchParticipant(ctx context.Context, streamUUID, participantUU
 		Name:     p.Name,
 		AvatarID: p.AvatarID,
 		Status:   p.Status,
+		Role:     participantRole(p),
 		Host:     false,
 	})
 
@@ -208,6 +254,7 @@ func (s *Server) PatchParticipant(ctx context.Context, streamUUID, participantUU
 		AvatarID: p.AvatarID,
 		IP:       p.IP,
 		Status:   p.Status,
+		Role:     participantRole(p),
 	}, nil
 }
 
@@ -219,8 +266,8 @@ func (s *Server) addNewParticipant(streamUUID string, p service.StreamParticipan
 		return
 	}
 
-	module := stream.(streamModule)
-	if err := module.handler.NewParticipant(p); err != nil {
+	module := stream.(*streamModule)
+	if err := module.streamHandler().NewParticipant(p); err != nil {
 		logrus.Errorf("could not add participant to the %s stream: %v", streamUUID, err)
 	}
 }
@@ -233,8 +280,8 @@ func (s *Server) updateParticipantInfo(streamUUID string, p service.StreamPartic
 		return
 	}
 
-	module := stream.(streamModule)
-	if err := module.handler.ChangeParticipantInfo(p); err != nil {
+	module := stream.(*streamModule)
+	if err := module.streamHandler().ChangeParticipantInfo(p); err != nil {
 		logrus.Errorf("could not change participant %s info: %v", p.UUID, err)
 	}
 }
@@ -0,0 +1,101 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/code-cord/cc.core.server/service"
+)
+
+var errStreamNotPaused = errors.New("stream is not paused")
+
+// PauseStream suspends the instance of the running stream.
+//
+// The state of the stream is kept, but it can't serve any requests until it's resumed.
+func (s *Server) PauseStream(ctx context.Context, streamUUID string) error {
+	stream, ok := s.streams.Load(streamUUID)
+	if !ok {
+		return fmt.Errorf("could not find running stream by UUID %s", streamUUID)
+	}
+
+	if err := stream.(*streamModule).pause(ctx); err != nil {
+		return err
+	}
+
+	return s.updateStreamStatus(streamUUID, service.StreamStatusPaused)
+}
+
+// ResumeStream resumes the instance of the paused stream.
+func (s *Server) ResumeStream(ctx context.Context, streamUUID string) error {
+	stream, ok := s.streams.Load(streamUUID)
+	if !ok {
+		return fmt.Errorf("could not find running stream by UUID %s", streamUUID)
+	}
+
+	if err := stream.(*streamModule).resume(ctx); err != nil {
+		return err
+	}
+
+	return s.updateStreamStatus(streamUUID, service.StreamStatusRunning)
+}
+
+func (s *Server) updateStreamStatus(streamUUID string, status service.StreamStatus) error {
+	_, err := s.updateStreamInfo(streamUUID, func(info *streamInfo) error {
+		info.Status = status
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("could not store %s stream status: %v", streamUUID, err)
+	}
+
+	return nil
+}
+
+func (m *streamModule) pause(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.isFinished() {
+		return errStreamFinished
+	}
+
+	if m.paused {
+		return service.ErrStreamPaused
+	}
+
+	if err := m.Pause(ctx); err != nil {
+		return err
+	}
+	m.paused = true
+
+	return nil
+}
+
+func (m *streamModule) resume(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.isFinished() {
+		return errStreamFinished
+	}
+
+	if !m.paused {
+		return errStreamNotPaused
+	}
+
+	if err := m.Resume(ctx); err != nil {
+		return err
+	}
+	m.paused = false
+
+	return nil
+}
+
+func (m *streamModule) isPaused() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.paused
+}
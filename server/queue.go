@@ -0,0 +1,254 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/code-cord/cc.core.server/service"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultStreamQueueSize          = 100
+	defaultStreamQueueCheckInterval = 5 * time.Second
+	defaultStreamQueueTTL           = time.Hour
+	defaultQueueTicketTTL           = 10 * time.Minute
+)
+
+var errQueueTicketExpired = errors.New("stream has been waiting in the queue for too long")
+
+// streamQueue represents queue of the streams waiting for the stream quotas.
+//
+// Each change of the queue is broadcasted by closing the changed channel.
+type streamQueue struct {
+	mu      sync.Mutex
+	pending []*queueTicket
+	tickets map[string]*queueTicket
+	changed chan struct{}
+	wake    chan struct{}
+}
+
+// queueTicket represents queued stream model.
+type queueTicket struct {
+	id         string
+	cfg        service.StreamConfig
+	stream     *service.StreamOwnerInfo
+	err        error
+	queuedAt   time.Time
+	resolvedAt time.Time
+}
+
+func newStreamQueue() *streamQueue {
+	return &streamQueue{
+		tickets: make(map[string]*queueTicket),
+		changed: make(chan struct{}),
+		wake:    make(chan struct{}, 1),
+	}
+}
+
+// QueueStream puts a new stream to the queue.
+//
+// The stream is created once it fits the stream quotas.
+func (s *Server) QueueStream(ctx context.Context, cfg service.StreamConfig) (
+	*service.StreamQueueTicket, error) {
+	s.queue.mu.Lock()
+	defer s.queue.mu.Unlock()
+
+	if len(s.queue.pending) >= defaultStreamQueueSize {
+		return nil, errors.New("stream queue is full")
+	}
+
+	ticket := &queueTicket{
+		id:       uuid.New().String(),
+		cfg:      cfg,
+		queuedAt: time.Now(),
+	}
+	s.queue.pending = append(s.queue.pending, ticket)
+	s.queue.tickets[ticket.id] = ticket
+	s.queue.notify()
+
+	return s.queue.ticketInfo(ticket), nil
+}
+
+// WatchStreamQueueTicket returns updates of the queued stream.
+//
+// Current state of the ticket is sent first. The channel is closed after the stream
+// is created or failed, or once the context is done.
+func (s *Server) WatchStreamQueueTicket(ctx context.Context, ticketID string) (
+	<-chan service.StreamQueueTicket, error) {
+	s.queue.mu.Lock()
+	ticket, ok := s.queue.tickets[ticketID]
+	s.queue.mu.Unlock()
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+
+	updates := make(chan service.StreamQueueTicket)
+	go func() {
+		defer close(updates)
+
+		for {
+			s.queue.mu.Lock()
+			info := s.queue.ticketInfo(ticket)
+			changed := s.queue.changed
+			s.queue.mu.Unlock()
+
+			select {
+			case updates <- *info:
+			case <-ctx.Done():
+				return
+			}
+
+			if info.Stream != nil || info.Error != "" {
+				return
+			}
+
+			select {
+			case <-changed:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return updates, nil
+}
+
+func (s *Server) runStreamQueue() {
+	ticker := time.NewTicker(defaultStreamQueueCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-s.queue.wake:
+		case <-s.done:
+			return
+		}
+
+		s.launchQueuedStreams()
+		s.queue.removeExpired(time.Now(), s.opts.StreamQueueTTL)
+	}
+}
+
+// launchQueuedStreams creates queued streams in order of the queue.
+//
+// Streams which still don't fit the quotas stay in the queue, so the streams of
+// other subjects may get ahead of them.
+func (s *Server) launchQueuedStreams() {
+	s.queue.mu.Lock()
+	pending := make([]*queueTicket, len(s.queue.pending))
+	copy(pending, s.queue.pending)
+	s.queue.mu.Unlock()
+
+	for _, ticket := range pending {
+		select {
+		case <-s.done:
+			return
+		default:
+		}
+
+		// the quota is checked first, so the streams which still don't fit it
+		// aren't prepared in vain.
+		if err := s.checkStreamQuota(ticket.cfg.Subject); err != nil {
+			if !errors.Is(err, service.ErrStreamQuotaExceeded) {
+				logrus.Errorf("could not check quota of queued stream: %v", err)
+			}
+			continue
+		}
+
+		stream, err := s.NewStream(context.Background(), ticket.cfg)
+		if errors.Is(err, service.ErrStreamQuotaExceeded) {
+			continue
+		}
+		if err != nil {
+			logrus.Errorf("could not create queued stream: %v", err)
+		}
+
+		s.queue.resolve(ticket, stream, err)
+	}
+}
+
+// wakeStreamQueue makes the queue try to create the queued streams.
+func (s *Server) wakeStreamQueue() {
+	select {
+	case s.queue.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (q *streamQueue) resolve(ticket *queueTicket, stream *service.StreamOwnerInfo, err error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for i := range q.pending {
+		if q.pending[i] == ticket {
+			q.pending = append(q.pending[:i], q.pending[i+1:]...)
+			break
+		}
+	}
+
+	ticket.stream = stream
+	ticket.err = err
+	ticket.resolvedAt = time.Now()
+	q.notify()
+}
+
+// removeExpired fails the streams waiting in the queue longer than the queue TTL and
+// forgets the tickets resolved longer than the ticket TTL ago.
+func (q *streamQueue) removeExpired(now time.Time, queueTTL time.Duration) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	pending := q.pending[:0]
+	for _, ticket := range q.pending {
+		if now.Sub(ticket.queuedAt) <= queueTTL {
+			pending = append(pending, ticket)
+			continue
+		}
+
+		ticket.err = errQueueTicketExpired
+		ticket.resolvedAt = now
+	}
+	if len(pending) != len(q.pending) {
+		q.pending = pending
+		q.notify()
+	}
+
+	for id, ticket := range q.tickets {
+		if !ticket.resolvedAt.IsZero() && now.Sub(ticket.resolvedAt) > defaultQueueTicketTTL {
+			delete(q.tickets, id)
+		}
+	}
+}
+
+// notify broadcasts the queue change, it must be called with the lock held.
+func (q *streamQueue) notify() {
+	close(q.changed)
+	q.changed = make(chan struct{})
+}
+
+// ticketInfo returns state of the ticket, it must be called with the lock held.
+func (q *streamQueue) ticketInfo(ticket *queueTicket) *service.StreamQueueTicket {
+	info := service.StreamQueueTicket{
+		Ticket:  ticket.id,
+		Subject: ticket.cfg.Subject,
+		Stream:  ticket.stream,
+	}
+	if ticket.err != nil {
+		info.Error = ticket.err.Error()
+	}
+
+	for i := range q.pending {
+		if q.pending[i] == ticket {
+			info.Position = i + 1
+			break
+		}
+	}
+
+	return &info
+}
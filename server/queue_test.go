@@ -0,0 +1,152 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/code-cord/cc.core.server/service"
+)
+
+// newTestServer returns server running the streams in process.
+func newTestServer(t *testing.T, opts ...Option) *Server {
+	t.Helper()
+
+	opts = append([]Option{
+		DataFolder(t.TempDir()),
+		StreamStopTimeout(time.Second),
+		LaunchMode(service.StreamLaunchModeInProcess, InProcessLaunchMode(http.NotFoundHandler())),
+	}, opts...)
+
+	s, err := New(opts...)
+	if err != nil {
+		t.Fatalf("could not init server: %v", err)
+	}
+	t.Cleanup(func() {
+		s.Stop(context.Background())
+	})
+
+	return s
+}
+
+func testStreamConfig() service.StreamConfig {
+	return service.StreamConfig{
+		Name:    "test stream",
+		Subject: "subject",
+		Join: service.StreamJoinPolicyConfig{
+			JoinPolicy: service.JoinPolicyAuto,
+		},
+		Launch: service.StreamLaunchConfig{
+			Mode: service.StreamLaunchModeInProcess,
+		},
+		Host: service.StreamHostConfig{
+			Username: "stream host",
+		},
+	}
+}
+
+func TestCheckStreamQuota(t *testing.T) {
+	s := newTestServer(t, StreamQuota(1, 0, 0, 0))
+
+	// the quota isn't reserved by the check.
+	for i := 0; i < 2; i++ {
+		if err := s.checkStreamQuota("subject"); err != nil {
+			t.Fatalf("could not check stream quota: %v", err)
+		}
+	}
+
+	release, err := s.acquireStreamQuota("subject")
+	if err != nil {
+		t.Fatalf("could not acquire stream quota: %v", err)
+	}
+	if err := s.checkStreamQuota("other"); !errors.Is(err, service.ErrStreamQuotaExceeded) {
+		t.Errorf("unexpected quota error: %v", err)
+	}
+
+	release()
+	if err := s.checkStreamQuota("other"); err != nil {
+		t.Errorf("released quota is still used: %v", err)
+	}
+}
+
+func TestLaunchQueuedStreams(t *testing.T) {
+	s := newTestServer(t, StreamQuota(1, 0, 0, 0))
+
+	release, err := s.acquireStreamQuota("other")
+	if err != nil {
+		t.Fatalf("could not acquire stream quota: %v", err)
+	}
+
+	ticket, err := s.QueueStream(context.Background(), testStreamConfig())
+	if err != nil {
+		t.Fatalf("could not queue stream: %v", err)
+	}
+
+	// the stream waits for the quota.
+	s.launchQueuedStreams()
+	info := queuedTicket(t, s, ticket.Ticket)
+	if info.Position != 1 || info.Stream != nil || info.Error != "" {
+		t.Fatalf("unexpected ticket before the quota is released: %+v", info)
+	}
+
+	release()
+	s.launchQueuedStreams()
+	info = queuedTicket(t, s, ticket.Ticket)
+	if info.Stream == nil {
+		t.Fatalf("queued stream isn't created: %+v", info)
+	}
+	if info.Position != 0 {
+		t.Errorf("created stream is still queued at %d", info.Position)
+	}
+}
+
+func TestStreamQueueExpiration(t *testing.T) {
+	s := newTestServer(t, StreamQuota(1, 0, 0, 0))
+
+	release, err := s.acquireStreamQuota("other")
+	if err != nil {
+		t.Fatalf("could not acquire stream quota: %v", err)
+	}
+	defer release()
+
+	ticket, err := s.QueueStream(context.Background(), testStreamConfig())
+	if err != nil {
+		t.Fatalf("could not queue stream: %v", err)
+	}
+
+	now := time.Now()
+	s.queue.removeExpired(now, time.Hour)
+	if info := queuedTicket(t, s, ticket.Ticket); info.Position != 1 {
+		t.Fatalf("ticket is expired too early: %+v", info)
+	}
+
+	now = now.Add(2 * time.Hour)
+	s.queue.removeExpired(now, time.Hour)
+	info := queuedTicket(t, s, ticket.Ticket)
+	if info.Position != 0 || info.Error != errQueueTicketExpired.Error() {
+		t.Fatalf("unexpected expired ticket: %+v", info)
+	}
+
+	// the resolved ticket is forgotten after a while.
+	s.queue.removeExpired(now.Add(defaultQueueTicketTTL+time.Minute), time.Hour)
+	if _, err := s.WatchStreamQueueTicket(context.Background(), ticket.Ticket); err == nil {
+		t.Error("expired ticket isn't removed")
+	}
+}
+
+// queuedTicket returns current state of the queue ticket.
+func queuedTicket(t *testing.T, s *Server, ticketID string) service.StreamQueueTicket {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	updates, err := s.WatchStreamQueueTicket(ctx, ticketID)
+	if err != nil {
+		t.Fatalf("could not watch queue ticket: %v", err)
+	}
+
+	return <-updates
+}
@@ -0,0 +1,196 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/code-cord/cc.core.server/service"
+)
+
+const (
+	defaultQuotaPeriod = 30 * 24 * time.Hour
+)
+
+// streamQuota represents state of the streams being launched which aren't stored
+// as running yet.
+type streamQuota struct {
+	mu      sync.Mutex
+	total   int
+	pending map[string]int
+}
+
+func newStreamQuota() *streamQuota {
+	return &streamQuota{
+		pending: make(map[string]int),
+	}
+}
+
+// QuotaUsage returns current usage of the stream quotas.
+//
+// Stream time of the subjects is counted within the quota period up to now.
+func (s *Server) QuotaUsage(ctx context.Context) (*service.QuotaUsage, error) {
+	running, subjects, err := s.streamUsage(time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	usage := service.QuotaUsage{
+		Limits:   s.quotaLimits(),
+		Running:  running,
+		Subjects: make([]service.SubjectQuotaUsage, 0, len(subjects)),
+	}
+	for _, subjectUsage := range subjects {
+		usage.Subjects = append(usage.Subjects, *subjectUsage)
+	}
+	sort.Slice(usage.Subjects, func(i, j int) bool {
+		return usage.Subjects[i].Subject < usage.Subjects[j].Subject
+	})
+
+	return &usage, nil
+}
+
+// acquireStreamQuota reserves quota for the new stream of the subject.
+//
+// The returned func has to be called to release the reservation once the stream
+// is stored. Per subject quotas aren't applied to the streams without subject.
+func (s *Server) acquireStreamQuota(subject string) (func(), error) {
+	s.quota.mu.Lock()
+	defer s.quota.mu.Unlock()
+
+	running, subjects, err := s.streamUsage(time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	maxStreams := s.opts.MaxStreams
+	if maxStreams > 0 && running+s.quota.total >= maxStreams {
+		return nil, fmt.Errorf("%w: max %d streams are running already",
+			service.ErrStreamQuotaExceeded, maxStreams)
+	}
+
+	if subject != "" {
+		var usage service.SubjectQuotaUsage
+		if subjectUsage, ok := subjects[subject]; ok {
+			usage = *subjectUsage
+		}
+
+		maxSubjectStreams := s.opts.MaxSubjectStreams
+		if maxSubjectStreams > 0 && usage.Running+s.quota.pending[subject] >= maxSubjectStreams {
+			return nil, fmt.Errorf("%w: max %d streams of %s are running already",
+				service.ErrStreamQuotaExceeded, maxSubjectStreams, subject)
+		}
+
+		maxStreamTime := s.opts.MaxSubjectStreamTime
+		if maxStreamTime > 0 && usage.StreamTime >= maxStreamTime {
+			return nil, fmt.Errorf("%w: %s stream time of %s is used up",
+				service.ErrStreamQuotaExceeded, maxStreamTime, subject)
+		}
+	}
+
+	s.quota.total++
+	s.quota.pending[subject]++
+
+	return func() {
+		s.quota.mu.Lock()
+		defer s.quota.mu.Unlock()
+
+		s.quota.total--
+		s.quota.pending[subject]--
+		if s.quota.pending[subject] <= 0 {
+			delete(s.quota.pending, subject)
+		}
+	}, nil
+}
+
+// checkStreamQuota makes sure the new stream of the subject fits the stream quotas
+// without reserving the quota.
+func (s *Server) checkStreamQuota(subject string) error {
+	release, err := s.acquireStreamQuota(subject)
+	if err != nil {
+		return err
+	}
+	release()
+
+	return nil
+}
+
+// streamUsage returns number of the running streams along with the usage of
+// the subjects.
+func (s *Server) streamUsage(now time.Time) (
+	int, map[string]*service.SubjectQuotaUsage, error) {
+	cursor, err := s.streamStorage.Default().All()
+	if err != nil {
+		return 0, nil, fmt.Errorf("could not fetch streams from storage: %v", err)
+	}
+	defer cursor.Close()
+
+	periodStart := now.Add(-s.opts.QuotaPeriod)
+	subjects := make(map[string]*service.SubjectQuotaUsage)
+	var running int
+	for rv, hasNext := cursor.First(); hasNext; rv, hasNext = cursor.Next() {
+		var stream streamInfo
+		if err := rv.Decode(&stream, json.Unmarshal); err != nil {
+			return 0, nil, fmt.Errorf("could not parse stream info: %v", err)
+		}
+
+		isRunning := stream.Status == service.StreamStatusRunning ||
+			stream.Status == service.StreamStatusPaused
+		if isRunning {
+			running++
+		}
+
+		if stream.Subject == "" {
+			continue
+		}
+
+		usage, ok := subjects[stream.Subject]
+		if !ok {
+			usage = &service.SubjectQuotaUsage{
+				Subject: stream.Subject,
+			}
+			subjects[stream.Subject] = usage
+		}
+		if isRunning {
+			usage.Running++
+		}
+		usage.StreamTime += streamTimeWithin(&stream, periodStart, now)
+	}
+
+	return running, subjects, nil
+}
+
+func (s *Server) quotaLimits() service.StreamQuota {
+	return service.StreamQuota{
+		MaxStreams:           s.opts.MaxStreams,
+		MaxSubjectStreams:    s.opts.MaxSubjectStreams,
+		MaxSubjectStreamTime: s.opts.MaxSubjectStreamTime,
+		Period:               s.opts.QuotaPeriod,
+	}
+}
+
+// streamTimeWithin returns how long the stream has been started within the period.
+func streamTimeWithin(stream *streamInfo, from, to time.Time) time.Duration {
+	if stream.StartedAt.IsZero() {
+		return 0
+	}
+
+	start := stream.StartedAt
+	if start.Before(from) {
+		start = from
+	}
+
+	end := to
+	if stream.FinishedAt != nil && stream.FinishedAt.Before(to) {
+		end = *stream.FinishedAt
+	}
+
+	if !end.After(start) {
+		return 0
+	}
+
+	return end.Sub(start)
+}
@@ -0,0 +1,290 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/code-cord/cc.core.server/service"
+	"github.com/code-cord/cc.core.server/stream"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultStreamDialTimeout = time.Second
+	defaultStreamPIDFolder   = "pid"
+	pidFileExt               = ".pid"
+)
+
+// errStreamUnidentified is returned when something is still serving the stream address,
+// but it can't be identified as the stream instance.
+var errStreamUnidentified = errors.New("stream could not be identified")
+
+// reconcileStreams brings streams marked as running or paused in the storage in line
+// with the streams served by the current server instance.
+//
+// After a crash or restart the server has no handle to the previously started
+// streams, so each of them is probed. Alive streams are re-adopted if possible,
+// otherwise they are stopped and finished. Paused streams are resumed on re-adoption.
+func (s *Server) reconcileStreams(ctx context.Context) error {
+	if err := s.killStreamStragglers(ctx); err != nil {
+		logrus.Errorf("could not kill stream stragglers: %v", err)
+	}
+
+	streams, err := s.streamsFromStorage(service.StreamStatusRunning)
+	if err != nil {
+		return err
+	}
+
+	pausedStreams, err := s.streamsFromStorage(service.StreamStatusPaused)
+	if err != nil {
+		return err
+	}
+	streams = append(streams, pausedStreams...)
+
+	for i := range streams {
+		s.reconcileStream(ctx, &streams[i])
+	}
+
+	return nil
+}
+
+func (s *Server) reconcileStream(ctx context.Context, info *streamInfo) {
+	if _, ok := s.streams.Load(info.UUID); ok {
+		return
+	}
+
+	orphan, err := s.findOrphanedStream(ctx, info)
+	switch {
+	case err == nil:
+		adoptErr := s.resumeOrphanedStream(ctx, info, orphan)
+		if adoptErr == nil {
+			adoptErr = s.adoptStream(ctx, info, orphan)
+		}
+		if adoptErr == nil {
+			logrus.Infof("stream %s has been re-adopted", info.UUID)
+			return
+		}
+
+		logrus.Warnf("could not re-adopt %s stream: %v", info.UUID, adoptErr)
+		logrus.Infof("stopping orphaned %s stream", info.UUID)
+		if err := orphan.Stop(ctx); err != nil {
+			logrus.Errorf("could not stop orphaned %s stream: %v", info.UUID, err)
+		}
+	case errors.Is(err, os.ErrNotExist):
+		logrus.Infof("stream %s is not running anymore", info.UUID)
+	case errors.Is(err, errStreamUnidentified):
+		// an unknown process isn't stopped, but the stream isn't served anymore, so it's
+		// finished along with the reason.
+		logrus.Warnf("could not reconcile %s stream: %v", info.UUID, err)
+		s.killStreamWithError(ctx, info.UUID, err)
+		return
+	default:
+		logrus.Warnf("could not reconcile %s stream: %v", info.UUID, err)
+	}
+
+	s.killStream(ctx, info.UUID)
+}
+
+// killStreamStragglers terminates standalone stream processes left running by the
+// previous server run.
+//
+// Stragglers are found by the PID files of the streams in the data folder.
+func (s *Server) killStreamStragglers(ctx context.Context) error {
+	files, err := ioutil.ReadDir(path.Join(s.opts.DataFolder, defaultStreamPIDFolder))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		return fmt.Errorf("could not read PID files: %v", err)
+	}
+
+	for _, file := range files {
+		if file.IsDir() || path.Ext(file.Name()) != pidFileExt {
+			continue
+		}
+
+		streamUUID := strings.TrimSuffix(file.Name(), pidFileExt)
+		pidFile := streamPIDFile(s.opts.DataFolder, streamUUID)
+
+		straggler, err := stream.FindStandaloneStream(stream.StandaloneStreamConfig{
+			BinPath:     s.opts.BinFolder,
+			StopTimeout: s.opts.StreamStopTimeout,
+			PIDFile:     pidFile,
+		})
+		switch {
+		case err == nil:
+			logrus.Infof("stopping straggler process of %s stream", streamUUID)
+			if err := straggler.Stop(ctx); err != nil {
+				logrus.Errorf("could not stop straggler process of %s stream: %v", streamUUID, err)
+			}
+		case errors.Is(err, os.ErrNotExist):
+			if err := os.Remove(pidFile); err != nil && !os.IsNotExist(err) {
+				logrus.Errorf("could not remove stale %s PID file: %v", pidFile, err)
+			}
+		default:
+			logrus.Errorf("could not find straggler process of %s stream: %v", streamUUID, err)
+		}
+	}
+
+	return nil
+}
+
+func streamPIDFile(dataFolder, streamUUID string) string {
+	return path.Join(dataFolder, defaultStreamPIDFolder, streamUUID+pidFileExt)
+}
+
+func (s *Server) findOrphanedStream(ctx context.Context, info *streamInfo) (
+	service.Stream, error) {
+	switch info.LaunchMode {
+	case service.StreamLaunchModeDockerContainer:
+		return stream.FindDockerContainerStream(ctx, stream.DockerContainerStreamConfig{
+			StreamUUID:      info.UUID,
+			ContainerPrefix: s.opts.StreamContainerPrefix,
+			PreferedPort:    info.Port,
+			PreferedIP:      info.IP,
+			StopTimeout:     s.opts.StreamStopTimeout,
+		})
+	case service.StreamLaunchModeRemoteWorker:
+		return stream.FindRemoteWorkerStream(ctx, stream.RemoteWorkerStreamConfig{
+			StreamUUID:   info.UUID,
+			Worker:       s.opts.workerClientConfig(info.Worker),
+			PreferedIP:   info.Preferred.IP,
+			PreferedPort: info.Preferred.Port,
+			Limits: service.StreamResourceLimits{
+				CPU:    info.Limits.CPU,
+				Memory: info.Limits.Memory,
+				PIDs:   info.Limits.PIDs,
+			},
+			Env:   info.Env,
+			Args:  info.Args,
+			Image: info.Image,
+		})
+	case service.StreamLaunchModeInProcess:
+		// in-process streams never outlive the server.
+		return nil, os.ErrNotExist
+	case service.StreamLaunchModeStandaloneApp:
+		address := fmt.Sprintf("%s:%d", info.IP, info.Port)
+		if !isStreamReachable(address) {
+			return nil, os.ErrNotExist
+		}
+
+		return nil, fmt.Errorf(
+			"%w: process is still listening on %s", errStreamUnidentified, address)
+	}
+
+	return nil, fmt.Errorf("invalid launch mode: %v", info.LaunchMode)
+}
+
+func (s *Server) resumeOrphanedStream(
+	ctx context.Context, info *streamInfo, orphan service.Stream) error {
+	if info.Status != service.StreamStatusPaused {
+		return nil
+	}
+
+	if err := orphan.Resume(ctx); err != nil {
+		return err
+	}
+	info.Status = service.StreamStatusRunning
+
+	if err := s.streamStorage.Default().Store(info.UUID, info, json.Marshal); err != nil {
+		return fmt.Errorf("could not store %s stream status: %v", info.UUID, err)
+	}
+
+	return nil
+}
+
+func (s *Server) adoptStream(ctx context.Context, info *streamInfo, orphan service.Stream) error {
+	if info.Address == "" {
+		return errors.New("stream serve address is missing")
+	}
+
+	if _, err := s.streamKeys(info.UUID); err != nil {
+		return err
+	}
+
+	if err := connectToStream(info.Address, 1); err != nil {
+		return fmt.Errorf("could not connect to the stream: %v", err)
+	}
+
+	var (
+		adopted service.Stream
+		err     error
+	)
+	switch orphanStream := orphan.(type) {
+	case *stream.DockerContainerStream:
+		cfg := stream.DockerContainerStreamConfig{
+			StreamUUID:      info.UUID,
+			ContainerPrefix: s.opts.StreamContainerPrefix,
+			DockerImage:     streamImage(info.Image, s.opts.StreamImage),
+			PreferedPort:    info.Port,
+			PreferedIP:      info.IP,
+			StopTimeout:     s.opts.StreamStopTimeout,
+			Limits: service.StreamResourceLimits{
+				CPU:    info.Limits.CPU,
+				Memory: info.Limits.Memory,
+				PIDs:   info.Limits.PIDs,
+			},
+			Env:  info.Env,
+			Args: info.Args,
+		}
+		adopted, err = stream.NewDockerContainerStreamFromID(ctx, cfg, orphanStream.ContainerID())
+	case *stream.RemoteWorkerStream:
+		// remote worker stream is already waited for since it has been found.
+		adopted = orphanStream
+	default:
+		err = fmt.Errorf("%s stream can't be re-adopted", info.LaunchMode)
+	}
+	if err != nil {
+		return err
+	}
+
+	module := newStreamModule(adopted, info.Address, s.newStreamLogFile(info.UUID))
+	module.lifetime = newStreamLifetime(info.StartedAt, info.MaxDuration, info.IdleTimeout)
+	s.streams.Store(info.UUID, module)
+	go s.captureStreamLogs(info.UUID, module)
+	go s.listenStreamInterruptEvent(info.UUID, module)
+
+	return nil
+}
+
+func (s *Server) streamsFromStorage(status service.StreamStatus) ([]streamInfo, error) {
+	cursor, err := s.streamStorage.Default().All()
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch streams from storage: %v", err)
+	}
+	defer cursor.Close()
+
+	var streams []streamInfo
+	for rv, hasNext := cursor.First(); hasNext; rv, hasNext = cursor.Next() {
+		var stream streamInfo
+		if err := rv.Decode(&stream, json.Unmarshal); err != nil {
+			return nil, fmt.Errorf("could not parse stream info: %v", err)
+		}
+
+		if stream.Status == status {
+			streams = append(streams, stream)
+		}
+	}
+
+	return streams, nil
+}
+
+func isStreamReachable(address string) bool {
+	conn, err := net.DialTimeout("tcp", address, defaultStreamDialTimeout)
+	if err != nil {
+		return false
+	}
+
+	conn.Close()
+
+	return true
+}
@@ -0,0 +1,51 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+
+	"github.com/code-cord/cc.core.server/service"
+)
+
+func TestReconcileUnidentifiedStream(t *testing.T) {
+	s := newTestServer(t)
+
+	// something unknown is listening on the stream address.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("could not bind port: %v", err)
+	}
+	defer listener.Close()
+
+	info := streamInfo{
+		UUID:       "0c2f5e1a-7d4b-4e8a-9b3c-6f1d2a5e8c70",
+		Name:       "standalone stream",
+		IP:         "127.0.0.1",
+		Port:       listener.Addr().(*net.TCPAddr).Port,
+		LaunchMode: service.StreamLaunchModeStandaloneApp,
+		Status:     service.StreamStatusRunning,
+	}
+	if err := s.streamStorage.Default().Store(info.UUID, info, json.Marshal); err != nil {
+		t.Fatalf("could not store stream info: %v", err)
+	}
+
+	s.reconcileStream(context.Background(), &info)
+
+	stored, err := s.loadStreamInfo(info.UUID)
+	if err != nil {
+		t.Fatalf("could not load stream info: %v", err)
+	}
+	if stored.Status != service.StreamStatusFinished || stored.Error == "" {
+		t.Errorf("unidentified stream isn't finished: status %s, error %q",
+			stored.Status, stored.Error)
+	}
+
+	// the unknown process isn't stopped.
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatalf("unknown process is stopped: %v", err)
+	}
+	conn.Close()
+}
@@ -0,0 +1,219 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/code-cord/cc.core.server/service"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultStreamRestartMaxAttempts = 3
+	defaultStreamRestartBackoff     = time.Second
+	defaultStreamRestartMaxBackoff  = time.Minute
+	// the number of restart attempts is reset once the stream has been running for this period.
+	defaultStreamRestartResetPeriod = 10 * time.Minute
+)
+
+var (
+	errStreamFinished          = errors.New("stream has been finished")
+	errStreamRestartNotAllowed = errors.New("stream restart is not allowed by the restart policy")
+)
+
+type streamRestartInfo struct {
+	Policy      service.StreamRestartPolicy `json:"policy,omitempty"`
+	MaxAttempts int                         `json:"max,omitempty"`
+	Count       int                         `json:"count,omitempty"`
+	RestartedAt *time.Time                  `json:"restartedAt,omitempty"`
+}
+
+func (s *Server) listenStreamInterruptEvent(streamUUID string, module *streamModule) {
+	for {
+		var err error
+		select {
+		case err = <-module.InterruptNotification():
+		case <-module.done:
+			return
+		}
+
+		if err != nil {
+			logrus.Errorf("stream %s has been interrupted: %v", streamUUID, err)
+		}
+
+		restartErr := s.restartStream(streamUUID, module, err)
+		if restartErr == nil {
+			continue
+		}
+
+		if restartErr != errStreamFinished && restartErr != errStreamRestartNotAllowed {
+			logrus.Errorf("could not restart %s stream: %v", streamUUID, restartErr)
+		}
+
+		s.killStream(context.Background(), streamUUID)
+		return
+	}
+}
+
+// restartStream restarts interrupted stream according to its restart policy.
+//
+// Every attempt is delayed with exponential backoff. Once the stream is up again,
+// all of the current participants are sent to it. Max attempts limit the number of
+// the consecutive restarts, the attempts are reset once the stream has been running
+// stable for a while.
+func (s *Server) restartStream(streamUUID string, module *streamModule, exitErr error) error {
+	info, err := s.loadStreamInfo(streamUUID)
+	if err != nil {
+		return err
+	}
+
+	restart := info.Restart
+	if restart.Policy != service.StreamRestartPolicyOnFailure || exitErr == nil {
+		return errStreamRestartNotAllowed
+	}
+
+	maxAttempts := restart.MaxAttempts
+	if maxAttempts == 0 {
+		maxAttempts = defaultStreamRestartMaxAttempts
+	}
+
+	count := restart.Count
+	if restart.RestartedAt != nil &&
+		time.Since(*restart.RestartedAt) >= defaultStreamRestartResetPeriod {
+		count = 0
+	}
+
+	for count < maxAttempts {
+		backoff := streamRestartBackoff(count)
+		count++
+
+		logrus.Infof("restarting %s stream in %v (attempt %d of %d)",
+			streamUUID, backoff, count, maxAttempts)
+
+		select {
+		case <-time.After(backoff):
+		case <-module.done:
+			return errStreamFinished
+		}
+
+		startInfo, err := module.restart(context.Background())
+		if err == errStreamFinished {
+			return err
+		}
+		if err != nil {
+			logrus.Errorf("could not restart %s stream: %v", streamUUID, err)
+			continue
+		}
+
+		now := time.Now().UTC()
+		info, err = s.updateStreamInfo(streamUUID, func(info *streamInfo) error {
+			info.Restart.Count = count
+			info.Restart.RestartedAt = &now
+			info.IP = startInfo.IP
+			info.Port = startInfo.Port
+			info.Address = module.address()
+
+			return nil
+		})
+		if err == errStreamFinished {
+			return err
+		}
+		if err != nil {
+			logrus.Errorf("could not store %s stream restart data: %v", streamUUID, err)
+			info, err = s.loadStreamInfo(streamUUID)
+			if err != nil {
+				return err
+			}
+		}
+
+		go s.captureStreamLogs(streamUUID, module)
+		s.resendParticipants(module, info)
+
+		return nil
+	}
+
+	_, err = s.updateStreamInfo(streamUUID, func(info *streamInfo) error {
+		info.Restart.Count = count
+
+		return nil
+	})
+	if err != nil && err != errStreamFinished {
+		logrus.Errorf("could not store %s stream restart data: %v", streamUUID, err)
+	}
+
+	return fmt.Errorf("maximum number of restart attempts (%d) has been reached", maxAttempts)
+}
+
+func (s *Server) resendParticipants(module *streamModule, info *streamInfo) {
+	handler := module.streamHandler()
+
+	participants := []service.StreamParticipant{
+		{
+			UUID:     info.Host.UUID,
+			Name:     info.Host.Username,
+			AvatarID: info.Host.AvatarID,
+			Status:   service.ParticipantStatusActive,
+			Role:     service.ParticipantRoleHost,
+			Host:     true,
+		},
+	}
+
+	if participantRV := s.participantStorage.Default().Load(info.UUID); participantRV != nil {
+		var storageParticipants []participantInfo
+		if err := participantRV.Decode(&storageParticipants, json.Unmarshal); err != nil {
+			logrus.Errorf("could not decode %s stream participants data: %v", info.UUID, err)
+		}
+
+		for i := range storageParticipants {
+			p := &storageParticipants[i]
+			if p.Status != service.ParticipantStatusActive {
+				continue
+			}
+
+			participants = append(participants, service.StreamParticipant{
+				UUID:     p.UUID,
+				Name:     p.Name,
+				AvatarID: p.AvatarID,
+				Status:   p.Status,
+				Role:     participantRole(p),
+			})
+		}
+	}
+
+	for i := range participants {
+		if err := handler.NewParticipant(participants[i]); err != nil {
+			logrus.Errorf("could not send participant %s to the %s stream: %v",
+				participants[i].UUID, info.UUID, err)
+		}
+	}
+}
+
+func (s *Server) loadStreamInfo(streamUUID string) (*streamInfo, error) {
+	streamRV := s.streamStorage.Default().Load(streamUUID)
+	if streamRV == nil {
+		return nil, fmt.Errorf("could not find stream by UUID %s", streamUUID)
+	}
+
+	var info streamInfo
+	if err := streamRV.Decode(&info, json.Unmarshal); err != nil {
+		return nil, fmt.Errorf("could not decode stream data: %v", err)
+	}
+
+	return &info, nil
+}
+
+func streamRestartBackoff(attempt int) time.Duration {
+	backoff := defaultStreamRestartBackoff
+	for i := 0; i < attempt && backoff < defaultStreamRestartMaxBackoff; i++ {
+		backoff *= 2
+	}
+
+	if backoff > defaultStreamRestartMaxBackoff {
+		backoff = defaultStreamRestartMaxBackoff
+	}
+
+	return backoff
+}
@@ -0,0 +1,132 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/code-cord/cc.core.server/service"
+)
+
+// participantRecorder represents stream handler recording the participants sent to the stream.
+type participantRecorder struct {
+	mu           sync.Mutex
+	participants map[string]service.StreamParticipant
+}
+
+func (rec *participantRecorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/participant" && r.Method == http.MethodPost {
+		var p service.StreamParticipant
+		if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		rec.mu.Lock()
+		rec.participants[p.UUID] = p
+		rec.mu.Unlock()
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (rec *participantRecorder) count() int {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	return len(rec.participants)
+}
+
+func (rec *participantRecorder) received() map[string]service.StreamParticipant {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	participants := rec.participants
+	rec.participants = make(map[string]service.StreamParticipant)
+
+	return participants
+}
+
+func TestResendParticipants(t *testing.T) {
+	rec := participantRecorder{
+		participants: make(map[string]service.StreamParticipant),
+	}
+	s, err := New(
+		DataFolder(t.TempDir()),
+		StreamStopTimeout(time.Second),
+		LaunchMode(service.StreamLaunchModeInProcess, InProcessLaunchMode(&rec)),
+	)
+	if err != nil {
+		t.Fatalf("could not init server: %v", err)
+	}
+	defer s.Stop(context.Background())
+	ctx := context.Background()
+
+	stream, err := s.NewStream(ctx, testStreamConfig())
+	if err != nil {
+		t.Fatalf("could not create stream: %v", err)
+	}
+
+	for _, name := range []string{"viewer", "new host"} {
+		if _, err := s.JoinParticipant(ctx, stream.UUID, stream.JoinCode, service.Participant{
+			Name: name,
+		}); err != nil {
+			t.Fatalf("could not join %s: %v", name, err)
+		}
+	}
+	participants, err := s.StreamParticipants(ctx, stream.UUID)
+	if err != nil {
+		t.Fatalf("could not get participants: %v", err)
+	}
+	uuids := make(map[string]string)
+	for _, p := range participants {
+		uuids[p.Name] = p.UUID
+	}
+
+	if _, err := s.ChangeParticipantRole(ctx, stream.UUID, uuids["viewer"],
+		service.ParticipantRoleViewer); err != nil {
+		t.Fatalf("could not change participant role: %v", err)
+	}
+	if _, err := s.TransferStreamHost(ctx, stream.UUID, uuids["new host"]); err != nil {
+		t.Fatalf("could not transfer host: %v", err)
+	}
+
+	// wait for the joined participants to be sent to the stream.
+	for deadline := time.Now().Add(time.Second); rec.count() < 2; {
+		if time.Now().After(deadline) {
+			t.Fatal("joined participants aren't sent to the stream")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	rec.received()
+
+	module, ok := s.streams.Load(stream.UUID)
+	if !ok {
+		t.Fatal("stream isn't running")
+	}
+	info, err := s.loadStreamInfo(stream.UUID)
+	if err != nil {
+		t.Fatalf("could not load stream info: %v", err)
+	}
+	s.resendParticipants(module.(*streamModule), info)
+
+	received := rec.received()
+	want := map[string]service.ParticipantRole{
+		uuids["new host"]: service.ParticipantRoleHost,
+		uuids["viewer"]:   service.ParticipantRoleViewer,
+		stream.Host.UUID:  previousHostRole,
+	}
+	for uuid, role := range want {
+		p, ok := received[uuid]
+		if !ok {
+			t.Errorf("participant %s isn't sent to the stream", uuid)
+			continue
+		}
+		if p.Role != role || p.Host != (role == service.ParticipantRoleHost) {
+			t.Errorf("participant %s is sent with %s role, host %v, want %s", uuid, p.Role, p.Host, role)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/code-cord/cc.core.server/service"
+)
+
+const (
+	defaultParticipantRole = service.ParticipantRoleEditor
+)
+
+// participantRoleRanks represents hierarchy of the participant roles.
+var participantRoleRanks = map[service.ParticipantRole]int{
+	service.ParticipantRoleViewer: 1,
+	service.ParticipantRoleEditor: 2,
+	service.ParticipantRoleCoHost: 3,
+	service.ParticipantRoleHost:   4,
+}
+
+// ChangeParticipantRole changes role of the stream participant.
+//
+// Host role can't be assigned, the stream has a single host.
+func (s *Server) ChangeParticipantRole(ctx context.Context,
+	streamUUID, participantUUID string, role service.ParticipantRole) (*service.Participant, error) {
+	if role == service.ParticipantRoleHost {
+		return nil, fmt.Errorf("%s role can't be assigned", role)
+	}
+
+	var p participantInfo
+	err := s.updateParticipants(streamUUID, func(participants []participantInfo) (
+		[]participantInfo, error) {
+		for i := range participants {
+			if participants[i].UUID != participantUUID {
+				continue
+			}
+			if participants[i].Status != service.ParticipantStatusActive {
+				break
+			}
+
+			participants[i].Role = role
+			p = participants[i]
+
+			return participants, nil
+		}
+
+		return nil, fmt.Errorf("could not find active participant by UUID %s", participantUUID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	go s.updateParticipantInfo(streamUUID, service.StreamParticipant{
+		UUID:     p.UUID,
+		Name:     p.Name,
+		AvatarID: p.AvatarID,
+		Status:   p.Status,
+		Role:     p.Role,
+	})
+
+	return &service.Participant{
+		UUID:     p.UUID,
+		Name:     p.Name,
+		AvatarID: p.AvatarID,
+		IP:       p.IP,
+		Status:   p.Status,
+		Role:     p.Role,
+	}, nil
+}
+
+// outranks checks whether the role is higher than the other one.
+func outranks(role, other service.ParticipantRole) bool {
+	return participantRoleRanks[role] > participantRoleRanks[other]
+}
+
+// participantRole returns role of the participant.
+//
+// Participants joined before the roles were introduced get the default role.
+func participantRole(p *participantInfo) service.ParticipantRole {
+	if p.Role == "" {
+		return defaultParticipantRole
+	}
+
+	return p.Role
+}
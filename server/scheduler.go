@@ -0,0 +1,121 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/code-cord/cc.core.server/service"
+	"github.com/sirupsen/logrus"
+)
+
+// restoreScheduledStreams schedules streams which have been scheduled before the
+// server restart. Streams whose start time has passed are started right away.
+func (s *Server) restoreScheduledStreams() error {
+	streams, err := s.streamsFromStorage(service.StreamStatusScheduled)
+	if err != nil {
+		return err
+	}
+
+	for i := range streams {
+		stream := &streams[i]
+		if stream.ScheduledAt == nil {
+			logrus.Warnf("scheduled stream %s has no start time, starting it now", stream.UUID)
+			s.scheduleStream(stream.UUID, time.Now())
+			continue
+		}
+
+		s.scheduleStream(stream.UUID, *stream.ScheduledAt)
+	}
+
+	return nil
+}
+
+// scheduleStream arms start of the stream at the provided time.
+func (s *Server) scheduleStream(streamUUID string, startAt time.Time) {
+	timer := time.AfterFunc(time.Until(startAt), func() {
+		s.launchScheduledStream(streamUUID)
+	})
+	s.scheduled.Store(streamUUID, timer)
+
+	logrus.Infof("stream %s is scheduled to start at %s", streamUUID, startAt.Format(time.RFC3339))
+}
+
+// unscheduleStream cancels start of the scheduled stream.
+//
+// It reports whether the stream has been scheduled.
+func (s *Server) unscheduleStream(streamUUID string) bool {
+	timer, ok := s.scheduled.LoadAndDelete(streamUUID)
+	if ok {
+		timer.(*time.Timer).Stop()
+	}
+
+	return ok
+}
+
+func (s *Server) isStreamScheduled(streamUUID string) bool {
+	_, ok := s.scheduled.Load(streamUUID)
+	return ok
+}
+
+func (s *Server) launchScheduledStream(streamUUID string) {
+	if _, ok := s.scheduled.LoadAndDelete(streamUUID); !ok {
+		return
+	}
+
+	// the stream info is locked during the launch, so the stream can't be finished
+	// halfway through it.
+	unlock := s.lockStreamInfo(streamUUID)
+	launched, err := s.startScheduledStream(streamUUID)
+	unlock()
+
+	if err != nil {
+		logrus.Errorf("could not start scheduled %s stream: %v", streamUUID, err)
+		s.killStreamWithError(context.Background(), streamUUID, err)
+		return
+	}
+
+	// the server may have been stopped during the launch, so the stream has been
+	// missed by the shutdown.
+	select {
+	case <-s.done:
+		if launched {
+			s.killStream(context.Background(), streamUUID)
+		}
+	default:
+	}
+}
+
+// startScheduledStream launches the scheduled stream.
+//
+// It reports whether the stream has been launched, the stream isn't launched if
+// it isn't scheduled anymore or the server is stopped.
+func (s *Server) startScheduledStream(streamUUID string) (bool, error) {
+	select {
+	case <-s.done:
+		return false, nil
+	default:
+	}
+
+	info, err := s.loadStreamInfo(streamUUID)
+	if err != nil {
+		return false, err
+	}
+
+	if info.Status != service.StreamStatusScheduled {
+		return false, nil
+	}
+
+	logrus.Infof("starting scheduled %s stream", streamUUID)
+
+	releaseQuota, err := s.acquireStreamQuota(info.Subject)
+	if err != nil {
+		return false, err
+	}
+	defer releaseQuota()
+
+	if err := s.launchStream(context.Background(), info); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
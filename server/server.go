@@ -12,6 +12,7 @@ import (
 	"path"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/code-cord/cc.core.server/handler"
 	"github.com/code-cord/cc.core.server/handler/api"
@@ -33,8 +34,11 @@ const (
 	defaultAvatarStorageName      = "avatar.db"
 	defaultParticipantStorageName = "participant.db"
 	streamBucket                  = "stream"
+	streamKeyBucket               = "key"
+	templateBucket                = "template"
 	avatarBucket                  = "avatar"
 	participantBucket             = "participant"
+	revokedTokenBucket            = "revoked"
 )
 
 // Server represents code-cord server implementation model.
@@ -43,9 +47,21 @@ type Server struct {
 	httpServer         *http.Server
 	apiHttpServer      *http.Server
 	streams            *sync.Map
+	keys               *sync.Map
+	scheduled          *sync.Map
+	infoLocks          *sync.Map
+	launchModes        *launchModeRegistry
 	streamStorage      *storage.Storage
 	avatarStorage      *storage.Storage
 	participantStorage *storage.Storage
+	healthClient       *http.Client
+	ports              *util.PortPool
+	quota              *streamQuota
+	queue              *streamQueue
+	access             *streamAccessCache
+	revokedMu          sync.Mutex
+	stopOnce           sync.Once
+	done               chan struct{}
 }
 
 type rsaKeys struct {
@@ -62,7 +78,7 @@ func New(opt ...Option) (*Server, error) {
 
 	streamDB, err := storage.New(storage.Config{
 		DBPath:        path.Join(opts.DataFolder, defaultStreamStorageName),
-		Buckets:       []string{streamBucket},
+		Buckets:       []string{streamBucket, streamKeyBucket, templateBucket, revokedTokenBucket},
 		DefaultBucket: streamBucket,
 	})
 	if err != nil {
@@ -96,9 +112,26 @@ func New(opt ...Option) (*Server, error) {
 			Addr: fmt.Sprintf("%s:%d", defaultAPIServerHost, defaultAPIServerPort),
 		},
 		streams:            new(sync.Map),
+		keys:               new(sync.Map),
+		scheduled:          new(sync.Map),
+		infoLocks:          new(sync.Map),
+		launchModes:        newLaunchModeRegistry(),
 		streamStorage:      streamDB,
 		avatarStorage:      avatarDB,
 		participantStorage: participantDB,
+		healthClient: &http.Client{
+			Timeout: defaultHealthCheckTimeout,
+		},
+		ports:  util.NewPortPool(opts.portMin, opts.portMax),
+		quota:  newStreamQuota(),
+		queue:  newStreamQueue(),
+		access: newStreamAccessCache(),
+		done:   make(chan struct{}),
+	}
+	for name, factory := range opts.LaunchModes {
+		if err := s.RegisterLaunchMode(name, factory); err != nil {
+			return nil, fmt.Errorf("could not register launch mode: %v", err)
+		}
 	}
 	if opts.LogLevel != "" {
 		logrus.SetLevel(opts.logLevel)
@@ -107,6 +140,7 @@ func New(opt ...Option) (*Server, error) {
 		Server:               &s,
 		SeverSecurityEnabled: s.opts.ServerSecurityEnabled,
 		ServerPublicKey:      s.opts.publicKey,
+		TrustedProxies:       s.opts.trustedProxies,
 	})
 	s.apiHttpServer.Handler = api.New(api.Config{
 		Server: &s,
@@ -117,6 +151,25 @@ func New(opt ...Option) (*Server, error) {
 
 // Run runs server.
 func (s *Server) Run(ctx context.Context) (err error) {
+	// reconcile streams left from the previous server run.
+	if err := s.reconcileStreams(ctx); err != nil {
+		logrus.Errorf("could not reconcile streams: %v", err)
+	}
+
+	// schedule streams planned before the server restart.
+	if err := s.restoreScheduledStreams(); err != nil {
+		logrus.Errorf("could not restore scheduled streams: %v", err)
+	}
+
+	// run stream health checks.
+	go s.runHealthChecks()
+
+	// run stream lifetime checks.
+	go s.runLifetimeChecks()
+
+	// run creation of the queued streams.
+	go s.runStreamQueue()
+
 	// run API http server.
 	go func() {
 		logrus.Infof("starting API server at %s", s.apiHttpServer.Addr)
@@ -147,8 +200,26 @@ func (s *Server) Run(ctx context.Context) (err error) {
 }
 
 // Stop stops the running server.
-func (s *Server) Stop(ctx context.Context) error {
+//
+// Only the first call stops the server, subsequent calls do nothing.
+func (s *Server) Stop(ctx context.Context) (err error) {
+	s.stopOnce.Do(func() {
+		err = s.stop(ctx)
+	})
+
+	return
+}
+
+func (s *Server) stop(ctx context.Context) error {
+	close(s.done)
+
 	errs := make([]string, 0)
+	s.scheduled.Range(func(key, value interface{}) bool {
+		value.(*time.Timer).Stop()
+
+		return true
+	})
+
 	s.streams.Range(func(key, value interface{}) bool {
 		s.killStream(ctx, key.(string))
 
@@ -192,6 +263,7 @@ func (s *Server) Info() service.ServerInfo {
 		Description: s.opts.Description,
 		Version:     s.opts.Version,
 		Meta:        s.opts.Meta,
+		LaunchModes: s.LaunchModes(),
 	}
 }
 
@@ -243,6 +315,10 @@ func newServerOptions(opt ...Option) (*Options, error) {
 		o(&opts)
 	}
 
+	if len(opts.duplicateLaunchModes) != 0 {
+		return nil, fmt.Errorf("launch mode %s is passed more than once", opts.duplicateLaunchModes[0])
+	}
+
 	if opts.Address == "" {
 		opts.Address = defaultServerAddress()
 	}
@@ -271,14 +347,17 @@ func newServerOptions(opt ...Option) (*Options, error) {
 			return nil, fmt.Errorf("could not init docker cli client: %v", err)
 		}
 
-		logrus.Infof("pulling %s docker image...", opts.StreamImage)
-
-		_, err = cli.ImagePull(context.Background(), opts.StreamImage, types.ImagePullOptions{
-			All:          true,
-			RegistryAuth: opts.StreamImageRegistryAuth,
-		})
-		if err != nil {
-			return nil, fmt.Errorf("could not pull %s docker image: %v", opts.StreamImage, err)
+		images := append([]string{opts.StreamImage}, opts.AllowedStreamImages...)
+		for _, image := range images {
+			logrus.Infof("pulling %s docker image...", image)
+
+			_, err = cli.ImagePull(context.Background(), image, types.ImagePullOptions{
+				All:          true,
+				RegistryAuth: opts.StreamImageRegistryAuth,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("could not pull %s docker image: %v", image, err)
+			}
 		}
 	}
 
@@ -296,6 +375,33 @@ func newServerOptions(opt ...Option) (*Options, error) {
 		logrus.Warnf("could not mark %s directory as hidden: %v", opts.DataFolder, err)
 	}
 
+	if opts.MasterKeySecret != "" {
+		opts.masterKey = []byte(opts.MasterKeySecret)
+	} else {
+		if opts.MasterKeyPath == "" {
+			opts.MasterKeyPath = path.Join(opts.DataFolder, defaultMasterKeyFileName)
+			logrus.Warn("Master key is stored next to the data it protects! " +
+				"Please specify `--master-key-secret` or `--master-key` outside of the data folder")
+		}
+
+		masterKey, err := loadMasterKey(opts.MasterKeyPath)
+		if err != nil {
+			return nil, fmt.Errorf("could not load server master key: %v", err)
+		}
+		opts.masterKey = masterKey
+	}
+
+	if opts.WorkerTLSEnabled {
+		httpClient, err := newWorkerHTTPClient(opts.WorkerCACertFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not init remote worker client: %v", err)
+		}
+		opts.workerHTTPClient = httpClient
+	} else if len(opts.Workers) != 0 {
+		logrus.Warn("Remote workers are requested over plain http! " +
+			"Please specify `--worker-tls` flag to protect the worker token")
+	}
+
 	if !opts.ServerSecurityEnabled {
 		logrus.Warn("Server security is disabled!" +
 			"Please don't use this server in prod, or specify `--with-security-check` flag")
@@ -336,6 +442,54 @@ func newServerOptions(opt ...Option) (*Options, error) {
 		}
 	}
 
+	if opts.StreamCgroupParent == "" {
+		opts.StreamCgroupParent = defaultStreamCgroupParent
+	}
+
+	if opts.StreamFinishWarning == 0 {
+		opts.StreamFinishWarning = defaultStreamFinishWarning
+	}
+
+	if opts.StreamPortRange != "" {
+		var err error
+		opts.portMin, opts.portMax, err = util.ParsePortRange(opts.StreamPortRange)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse stream port range: %v", err)
+		}
+	}
+
+	if len(opts.TrustedProxies) != 0 {
+		var err error
+		opts.trustedProxies, err = util.ParseTrustedProxies(opts.TrustedProxies)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse trusted proxies: %v", err)
+		}
+	}
+
+	if opts.StreamAccessTokenTTL == 0 {
+		opts.StreamAccessTokenTTL = defaultStreamAccessTokenTTL
+	}
+
+	if opts.StreamRefreshTokenTTL == 0 {
+		opts.StreamRefreshTokenTTL = defaultStreamRefreshTokenTTL
+	}
+
+	if opts.JoinApprovalTimeout == 0 {
+		opts.JoinApprovalTimeout = defaultJoinApprovalTimeout
+	}
+
+	if opts.StreamQueueTTL == 0 {
+		opts.StreamQueueTTL = defaultStreamQueueTTL
+	}
+
+	if opts.QuotaPeriod == 0 {
+		opts.QuotaPeriod = defaultQuotaPeriod
+	}
+
+	if opts.HealthCheckInterval == 0 {
+		opts.HealthCheckInterval = defaultHealthCheckInterval
+	}
+
 	if opts.BinFolder == "" {
 		dir, err := os.Getwd()
 		if err != nil {
@@ -16,7 +16,6 @@ import (
 
 	"github.com/code-cord/cc.core.server/service"
 	"github.com/code-cord/cc.core.server/stream"
-	"github.com/golang-jwt/jwt"
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 )
@@ -25,14 +24,21 @@ const (
 	defaultConnectToStreamRetryCount   = 6
 	defaultConnectToStreamRetryTimeout = 500 * time.Millisecond
 	defaultStreamTokenType             = "bearer"
+	defaultPortCollisionRetryCount     = 3
+	defaultStreamLaunchMode            = service.StreamLaunchModeStandaloneApp
 )
 
 type streamModule struct {
 	service.Stream
 	pendingParticipants *sync.Map
-	rsaKeys             *rsaKeys
+	done                chan struct{}
+	mu                  sync.RWMutex
 	serveAddress        string
 	handler             service.StreamHandler
+	healthState         streamHealthState
+	logs                *streamLogFile
+	lifetime            streamLifetime
+	paused              bool
 }
 
 type streamInfo struct {
@@ -48,6 +54,24 @@ type streamInfo struct {
 	Status      service.StreamStatus     `json:"status"`
 	Join        streamJoinInfo           `json:"join"`
 	Host        streamHostInfo           `json:"host"`
+	Address     string                   `json:"addr,omitempty"`
+	ScheduledAt *time.Time               `json:"scheduledAt,omitempty"`
+	Restart     streamRestartInfo        `json:"restart"`
+	Health      service.StreamHealth     `json:"health,omitempty"`
+	Limits      streamLimitsInfo         `json:"limits"`
+	MaxDuration time.Duration            `json:"maxDuration,omitempty"`
+	IdleTimeout time.Duration            `json:"idleTimeout,omitempty"`
+	Preferred   streamPreferredInfo      `json:"preferred"`
+	Worker      string                   `json:"worker,omitempty"`
+	Error       string                   `json:"error,omitempty"`
+	Env         map[string]string        `json:"env,omitempty"`
+	Args        []string                 `json:"args,omitempty"`
+	Image       string                   `json:"image,omitempty"`
+}
+
+type streamPreferredInfo struct {
+	IP   string `json:"ip,omitempty"`
+	Port int    `json:"port,omitempty"`
 }
 
 type streamJoinInfo struct {
@@ -64,52 +88,85 @@ type streamHostInfo struct {
 
 type sortFn func(i, j int) bool
 
+// portResetter represents stream which port can be changed between starts.
+type portResetter interface {
+	ResetPort() bool
+}
+
 // NewStream starts a new stream.
+//
+// If the start time of the stream is in the future, the stream is scheduled to
+// be started at that time.
 func (s *Server) NewStream(ctx context.Context, cfg service.StreamConfig) (
 	*service.StreamOwnerInfo, error) {
-	// generate stream access keys.
-	keys, err := generateRSAKeys()
-	if err != nil {
-		return nil, fmt.Errorf("could not generate stream access keys: %v", err)
+	var err error
+	if cfg.Template != "" {
+		tpl, err := s.StreamTemplate(ctx, cfg.Template)
+		if err != nil {
+			return nil, fmt.Errorf("could not load %s template: %v", cfg.Template, err)
+		}
+		cfg = applyStreamTemplate(cfg, tpl)
+	}
+
+	if cfg.Join.JoinPolicy == "" {
+		return nil, errors.New("stream join policy is not set")
 	}
 
 	if cfg.Launch.Mode == "" {
-		cfg.Launch.Mode = service.StreamLaunchModeStandaloneApp
+		cfg.Launch.Mode = defaultStreamLaunchMode
 	}
 
-	streamUUID := uuid.New().String()
-	hostUUID := uuid.New().String()
-	streamHandler, err := s.newStreamHandler(cfg, streamUUID)
+	cfg.Launch.Limits, err = s.streamResourceLimits(cfg.Launch.Limits)
 	if err != nil {
 		return nil, err
 	}
 
-	// generate host access token.
-	token, err := generateStreamAccessToken(streamUUID, hostUUID, true, keys.privateKey)
-	if err != nil {
-		return nil, fmt.Errorf("could not authorize host user for the stream: %v", err)
+	if err := s.checkStreamImage(cfg.Launch); err != nil {
+		return nil, err
 	}
 
-	// start stream and connect.
-	startInfo, err := startStreamAndConnect(ctx, streamHandler)
+	if err := checkStreamEnv(cfg.Launch); err != nil {
+		return nil, err
+	}
+
+	if err := checkStreamArgs(cfg.Launch); err != nil {
+		return nil, err
+	}
+
+	// make sure the stream can be launched.
+	if err := s.checkLaunchMode(cfg.Launch); err != nil {
+		return nil, err
+	}
+
+	// the stream access keys are generated once the stream fits the quotas.
+	releaseQuota, err := s.acquireStreamQuota(cfg.Subject)
 	if err != nil {
 		return nil, err
 	}
+	defer releaseQuota()
 
-	// listening stream interrupt event.
-	go s.listenStreamInterruptEvent(streamUUID, streamHandler.InterruptNotification())
+	streamUUID := uuid.New().String()
+	hostUUID := uuid.New().String()
+
+	// generate stream access keys.
+	keys, err := generateRSAKeys()
+	if err != nil {
+		return nil, fmt.Errorf("could not generate stream access keys: %v", err)
+	}
+
+	// generate host access token.
+	token, err := s.generateStreamTokens(
+		streamUUID, hostUUID, service.ParticipantRoleHost, keys.privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("could not authorize host user for the stream: %v", err)
+	}
 
-	// store stream data.
 	info := streamInfo{
 		UUID:        streamUUID,
 		Name:        cfg.Name,
 		Description: cfg.Description,
-		IP:          startInfo.IP,
-		Port:        startInfo.Port,
 		LaunchMode:  cfg.Launch.Mode,
-		StartedAt:   time.Now().UTC(),
 		Subject:     cfg.Subject,
-		Status:      service.StreamStatusRunning,
 		Join: streamJoinInfo{
 			Code:   cfg.Join.JoinCode,
 			Policy: cfg.Join.JoinPolicy,
@@ -120,31 +177,107 @@ func (s *Server) NewStream(ctx context.Context, cfg service.StreamConfig) (
 			AvatarID: cfg.Host.AvatarID,
 			IP:       cfg.Host.IP,
 		},
+		Restart: streamRestartInfo{
+			Policy:      cfg.Launch.Restart.Policy,
+			MaxAttempts: cfg.Launch.Restart.MaxAttempts,
+		},
+		Limits: streamLimitsInfo{
+			CPU:    cfg.Launch.Limits.CPU,
+			Memory: cfg.Launch.Limits.Memory,
+			PIDs:   cfg.Launch.Limits.PIDs,
+		},
+		MaxDuration: cfg.MaxDuration,
+		IdleTimeout: cfg.IdleTimeout,
+		Preferred: streamPreferredInfo{
+			IP:   cfg.Launch.PreferredIP,
+			Port: cfg.Launch.PreferredPort,
+		},
+		Env:   cfg.Launch.Env,
+		Args:  cfg.Launch.Args,
+		Image: cfg.Launch.Image,
 	}
-	if err := s.streamStorage.Default().Store(streamUUID, info, json.Marshal); err != nil {
+	if err := s.storeStreamKeys(streamUUID, keys); err != nil {
+		return nil, fmt.Errorf("could not store %s stream access keys: %v", streamUUID, err)
+	}
+
+	if cfg.StartAt.After(time.Now()) {
+		scheduledAt := cfg.StartAt.UTC()
+		info.Status = service.StreamStatusScheduled
+		info.ScheduledAt = &scheduledAt
+
+		if err := s.streamStorage.Default().Store(streamUUID, info, json.Marshal); err != nil {
+			s.killStream(ctx, streamUUID)
+			return nil, fmt.Errorf("could not store %s stream data: %v", streamUUID, err)
+		}
+		s.scheduleStream(streamUUID, scheduledAt)
+
+		return buildStreamOwnerInfo(&info, token), nil
+	}
+
+	unlock := s.lockStreamInfo(streamUUID)
+	err = s.launchStream(ctx, &info)
+	unlock()
+	if err != nil {
 		s.killStream(ctx, streamUUID)
-		return nil, fmt.Errorf("could not store %s stream data: %v", streamUUID, err)
+		return nil, err
 	}
 
-	serveAddress := fmt.Sprintf("%s:%d", startInfo.IP, startInfo.Port)
-	module := streamModule{
-		pendingParticipants: new(sync.Map),
-		rsaKeys:             keys,
-		Stream:              streamHandler,
-		serveAddress:        serveAddress,
-		handler:             NewStreamHandler(serveAddress),
+	return buildStreamOwnerInfo(&info, token), nil
+}
+
+// launchStream starts instance of the stream and marks the stream as running.
+//
+// The caller must hold the lock of the stream info, so the stream can't be finished
+// halfway through the launch.
+func (s *Server) launchStream(ctx context.Context, info *streamInfo) error {
+	streamUUID := info.UUID
+	streamHandler, err := s.newStreamHandler(streamLaunchConfig(info), streamUUID)
+	if err != nil {
+		return err
+	}
+
+	// start stream and connect.
+	startInfo, err := startStreamAndConnect(ctx, streamHandler)
+	if err != nil {
+		return err
 	}
+
+	// the stream is run by the worker it has been started on.
+	if remote, ok := streamHandler.(*stream.RemoteWorkerStream); ok {
+		info.Worker = remote.WorkerAddress()
+	}
+
+	serveAddress := fmt.Sprintf("%s:%d", startInfo.IP, startInfo.Port)
+	startedAt := time.Now().UTC()
+	module := newStreamModule(streamHandler, serveAddress, s.newStreamLogFile(streamUUID))
+	module.lifetime = newStreamLifetime(startedAt, info.MaxDuration, info.IdleTimeout)
 	s.streams.Store(streamUUID, module)
+	go s.captureStreamLogs(streamUUID, module)
+
+	// listening stream interrupt event.
+	go s.listenStreamInterruptEvent(streamUUID, module)
+
+	// store stream data.
+	info.IP = startInfo.IP
+	info.Port = startInfo.Port
+	info.Address = serveAddress
+	info.StartedAt = startedAt
+	info.Status = service.StreamStatusRunning
+	info.Health = service.StreamHealthHealthy
+	if err := s.streamStorage.Default().Store(streamUUID, info, json.Marshal); err != nil {
+		return fmt.Errorf("could not store %s stream data: %v", streamUUID, err)
+	}
 
 	go s.addNewParticipant(streamUUID, service.StreamParticipant{
-		UUID:     hostUUID,
-		Name:     cfg.Host.Username,
-		AvatarID: cfg.Host.AvatarID,
+		UUID:     info.Host.UUID,
+		Name:     info.Host.Username,
+		AvatarID: info.Host.AvatarID,
 		Status:   service.ParticipantStatusActive,
+		Role:     service.ParticipantRoleHost,
 		Host:     true,
 	})
 
-	return buildStreamOwnerInfo(&info, token), nil
+	return nil
 }
 
 // StreamInfo returns public stream info by stream UUID.
@@ -167,6 +300,9 @@ func (s *Server) StreamInfo(
 		JoinPolicy:  info.Join.Policy,
 		StartedAt:   info.StartedAt,
 		FinishedAt:  info.FinishedAt,
+		Health:      info.Health,
+		Status:      info.Status,
+		ScheduledAt: info.ScheduledAt,
 	}, nil
 }
 
@@ -177,14 +313,19 @@ func (s *Server) StreamAddress(ctx context.Context, streamUUID string) (string,
 		return "", fmt.Errorf("could not find running stream by UUID %s", streamUUID)
 	}
 
-	module := stream.(streamModule)
-	return module.serveAddress, nil
+	module := stream.(*streamModule)
+	if module.isPaused() {
+		return "", service.ErrStreamPaused
+	}
+	module.touchTraffic(time.Now().UTC())
+
+	return module.address(), nil
 }
 
 // FinishStream finishes running stream.
 func (s *Server) FinishStream(ctx context.Context, streamUUID string) error {
 	_, ok := s.streams.Load(streamUUID)
-	if !ok {
+	if !ok && !s.isStreamScheduled(streamUUID) {
 		return fmt.Errorf("could not find running stream by UUID %s", streamUUID)
 	}
 
@@ -195,51 +336,45 @@ func (s *Server) FinishStream(ctx context.Context, streamUUID string) error {
 
 // StreamKey returns stream public key info.
 func (s *Server) StreamKey(ctx context.Context, streamUUID string) (*rsa.PublicKey, error) {
-	streamValue, ok := s.streams.Load(streamUUID)
-	if !ok {
-		return nil, fmt.Errorf("could not find running stream by UUID %s", streamUUID)
+	keys, err := s.streamKeys(streamUUID)
+	if err != nil {
+		return nil, err
 	}
-	streamData := streamValue.(streamModule)
 
-	return streamData.rsaKeys.publicKey, nil
+	return keys.publicKey, nil
 }
 
 // PatchStream updates stream info.
 func (s *Server) PatchStream(
 	ctx context.Context, streamUUID string, cfg service.PatchStreamConfig) (
 	*service.StreamOwnerInfo, error) {
-	streamRV := s.streamStorage.Default().Load(streamUUID)
-	_, ok := s.streams.Load(streamUUID)
-	if !ok || streamRV == nil {
-		return nil, fmt.Errorf("could not find running stream by UUID %s", streamUUID)
-	}
-
-	var info streamInfo
-	if err := streamRV.Decode(&info, json.Unmarshal); err != nil {
-		return nil, fmt.Errorf("could not decode stream data: %v", err)
-	}
+	info, err := s.updateStreamInfo(streamUUID, func(info *streamInfo) error {
+		if cfg.Name != nil {
+			info.Name = *cfg.Name
+		}
 
-	if cfg.Name != nil {
-		info.Name = *cfg.Name
-	}
+		if cfg.Description != nil {
+			info.Description = *cfg.Description
+		}
 
-	if cfg.Description != nil {
-		info.Description = *cfg.Description
-	}
+		if cfg.Join != nil {
+			info.Join = streamJoinInfo{
+				Code:   cfg.Join.JoinCode,
+				Policy: cfg.Join.JoinPolicy,
+			}
+		}
 
-	if cfg.Join != nil {
-		info.Join = streamJoinInfo{
-			Code:   cfg.Join.JoinCode,
-			Policy: cfg.Join.JoinPolicy,
+		if cfg.Host != nil {
+			info.Host.Username = cfg.Host.Username
+			info.Host.AvatarID = cfg.Host.AvatarID
 		}
-	}
 
-	if cfg.Host != nil {
-		info.Host.Username = cfg.Host.Username
-		info.Host.AvatarID = cfg.Host.AvatarID
+		return nil
+	})
+	if err == errStreamFinished {
+		return nil, fmt.Errorf("could not find running stream by UUID %s", streamUUID)
 	}
-
-	if err := s.streamStorage.Default().Store(streamUUID, info, json.Marshal); err != nil {
+	if err != nil {
 		return nil, fmt.Errorf("could not update stream info: %v", err)
 	}
 
@@ -249,22 +384,22 @@ func (s *Server) PatchStream(
 			Name:     info.Host.Username,
 			AvatarID: info.Host.AvatarID,
 			Status:   service.ParticipantStatusActive,
+			Role:     service.ParticipantRoleHost,
 			Host:     true,
 		})
 	}
 
-	return buildStreamOwnerInfo(&info, ""), nil
+	return buildStreamOwnerInfo(info, nil), nil
 }
 
 // NewStreamHostToken generates new access token for the host of the stream.
 func (s *Server) NewStreamHostToken(ctx context.Context, streamUUID, subject string) (
 	*service.AuthInfo, error) {
 	streamRV := s.streamStorage.Default().Load(streamUUID)
-	streamValue, ok := s.streams.Load(streamUUID)
+	_, ok := s.streams.Load(streamUUID)
 	if !ok || streamRV == nil {
 		return nil, fmt.Errorf("could not find running stream by UUID %s", streamUUID)
 	}
-	streamData := streamValue.(streamModule)
 
 	var info streamInfo
 	if err := streamRV.Decode(&info, json.Unmarshal); err != nil {
@@ -275,16 +410,18 @@ func (s *Server) NewStreamHostToken(ctx context.Context, streamUUID, subject str
 		return nil, errors.New("could not verify stream subject")
 	}
 
-	token, err := generateStreamAccessToken(
-		streamUUID, info.Host.UUID, true, streamData.rsaKeys.privateKey)
+	keys, err := s.streamKeys(streamUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := s.generateStreamTokens(
+		streamUUID, info.Host.UUID, service.ParticipantRoleHost, keys.privateKey)
 	if err != nil {
 		return nil, fmt.Errorf("could not generate access token: %v", err)
 	}
 
-	return &service.AuthInfo{
-		AccessToken: token,
-		Type:        defaultStreamTokenType,
-	}, nil
+	return token, nil
 }
 
 // StreamList returns stream list.
@@ -294,6 +431,7 @@ func (s *Server) StreamList(ctx context.Context, filter service.StreamFilter) (
 	if err != nil {
 		return nil, fmt.Errorf("could not fetch streams from storage: %v", err)
 	}
+	defer cursor.Close()
 
 	streams := make([]service.StreamInfo, 0, s.streamStorage.Default().Size())
 	for rv, hasNext := cursor.First(); hasNext; rv, hasNext = cursor.Next() {
@@ -323,6 +461,16 @@ func (s *Server) StreamList(ctx context.Context, filter service.StreamFilter) (
 					AvatarID: stream.Host.AvatarID,
 					IP:       stream.Host.IP,
 				},
+				ScheduledAt: stream.ScheduledAt,
+				Restarts:    stream.Restart.Count,
+				Health:      stream.Health,
+				Limits: service.StreamResourceLimits{
+					CPU:    stream.Limits.CPU,
+					Memory: stream.Limits.Memory,
+					PIDs:   stream.Limits.PIDs,
+				},
+				Worker: stream.Worker,
+				Error:  stream.Error,
 			})
 		}
 	}
@@ -352,45 +500,37 @@ func (s *Server) StreamList(ctx context.Context, filter service.StreamFilter) (
 	}, nil
 }
 
-func (s *Server) newStreamHandler(cfg service.StreamConfig, streamUUID string) (service.Stream, error) {
-	switch cfg.Launch.Mode {
-	case service.StreamLaunchModeStandaloneApp:
-		return stream.NewStandaloneStream(stream.StandaloneStreamConfig{
-			PreferedIP:   cfg.Launch.PreferredIP,
-			PreferedPort: cfg.Launch.PreferredPort,
-			BinPath:      s.opts.BinFolder,
-		}), nil
-	case service.StreamLaunchModeDockerContainer:
-		return stream.NewDockerContainerStream(stream.DockerContainerStreamConfig{
-			StreamUUID:      streamUUID,
-			ContainerPrefix: s.opts.StreamContainerPrefix,
-			DockerImage:     s.opts.StreamImage,
-			PreferedPort:    cfg.Launch.PreferredPort,
-			PreferedIP:      cfg.Launch.PreferredIP,
-		}), nil
-	}
-
-	return nil, fmt.Errorf("invalid launch mode: %v", cfg.Launch.Mode)
+func (s *Server) killStream(ctx context.Context, streamUUID string) {
+	s.killStreamWithError(ctx, streamUUID, nil)
 }
 
-func (s *Server) listenStreamInterruptEvent(streamUUID string, intChan <-chan error) {
-	err := <-intChan
-	if err != nil {
-		logrus.Errorf("stream %s has been interrupted: %v", streamUUID, err)
-	}
+// killStreamWithError stops the stream and marks it as finished along with the reason.
+func (s *Server) killStreamWithError(ctx context.Context, streamUUID string, reason error) {
+	defer s.wakeStreamQueue()
 
-	s.killStream(context.Background(), streamUUID)
-}
+	unlock := s.lockStreamInfo(streamUUID)
+	defer unlock()
+	// the stream isn't served anymore, so the writers waiting for the lock skip their updates.
+	defer s.infoLocks.Delete(streamUUID)
 
-func (s *Server) killStream(ctx context.Context, streamUUID string) {
-	if streamValue, ok := s.streams.Load(streamUUID); ok {
-		stream := streamValue.(streamModule)
+	s.unscheduleStream(streamUUID)
+
+	if streamValue, ok := s.streams.LoadAndDelete(streamUUID); ok {
+		module := streamValue.(*streamModule)
 
-		if err := stream.Stop(ctx); err != nil {
+		if err := module.finish(ctx); err != nil {
 			logrus.Errorf("could not stop %s stream: %v", streamUUID, err)
 		}
+	}
+
+	s.ports.ReleaseOwner(streamUUID)
 
-		s.streams.Delete(streamUUID)
+	if err := s.deleteStreamKeys(streamUUID); err != nil {
+		logrus.Errorf("could not delete %s stream access keys: %v", streamUUID, err)
+	}
+
+	if err := s.deleteRevokedStreamTokens(streamUUID); err != nil {
+		logrus.Errorf("could not delete %s stream revoked tokens: %v", streamUUID, err)
 	}
 
 	streamRV := s.streamStorage.Default().Load(streamUUID)
@@ -407,6 +547,9 @@ func (s *Server) killStream(ctx context.Context, streamUUID string) {
 	now := time.Now().UTC()
 	stream.FinishedAt = &now
 	stream.Status = service.StreamStatusFinished
+	if reason != nil {
+		stream.Error = reason.Error()
+	}
 	if err := s.streamStorage.Default().Store(streamUUID, stream, json.Marshal); err != nil {
 		logrus.Errorf("could not store %s stream data to finish: %v", streamUUID, err)
 	}
@@ -519,16 +662,78 @@ func filterStreamsByStatus(
 	}
 }
 
-func generateStreamAccessToken(
-	streamUUID, participantUUID string, isHost bool, privateKey *rsa.PrivateKey) (string, error) {
-	claims := &jwt.MapClaims{
-		"streamUUID": streamUUID,
-		"UUID":       participantUUID,
-		"host":       isHost,
+func newStreamModule(
+	stream service.Stream, serveAddress string, logs *streamLogFile) *streamModule {
+	return &streamModule{
+		pendingParticipants: new(sync.Map),
+		done:                make(chan struct{}),
+		Stream:              stream,
+		serveAddress:        serveAddress,
+		handler:             NewStreamHandler(serveAddress),
+		logs:                logs,
+	}
+}
+
+func (m *streamModule) address() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.serveAddress
+}
+
+func (m *streamModule) streamHandler() service.StreamHandler {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.handler
+}
+
+// restart starts the stream instance again unless the stream has been finished.
+func (m *streamModule) restart(ctx context.Context) (*service.StartStreamInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.isFinished() {
+		return nil, errStreamFinished
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
-	return token.SignedString(privateKey)
+	startInfo, err := startStreamAndConnect(ctx, m.Stream)
+	if err != nil {
+		return nil, err
+	}
+
+	m.serveAddress = fmt.Sprintf("%s:%d", startInfo.IP, startInfo.Port)
+	m.handler = NewStreamHandler(m.serveAddress)
+	m.paused = false
+
+	return startInfo, nil
+}
+
+// finish stops the stream instance and marks stream module as finished.
+func (m *streamModule) finish(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.isFinished() {
+		return nil
+	}
+	close(m.done)
+
+	stopErr := m.Stop(ctx)
+	if err := m.logs.Close(); err != nil {
+		logrus.Errorf("could not close stream log file: %v", err)
+	}
+
+	return stopErr
+}
+
+func (m *streamModule) isFinished() bool {
+	select {
+	case <-m.done:
+		return true
+	default:
+		return false
+	}
 }
 
 func generateRSAKeys() (*rsaKeys, error) {
@@ -543,7 +748,32 @@ func generateRSAKeys() (*rsaKeys, error) {
 	}, nil
 }
 
+// startStreamAndConnect starts the stream instance and makes sure it's reachable.
+//
+// If the stream isn't bound to the preferred port, the start is retried on another
+// port since the reserved one may have been taken by a foreign process.
 func startStreamAndConnect(ctx context.Context, stream service.Stream) (
+	*service.StartStreamInfo, error) {
+	var err error
+	for i := 0; i < defaultPortCollisionRetryCount; i++ {
+		var startInfo *service.StartStreamInfo
+		startInfo, err = startStream(ctx, stream)
+		if err == nil {
+			return startInfo, nil
+		}
+
+		resetter, ok := stream.(portResetter)
+		if !ok || !resetter.ResetPort() {
+			return nil, err
+		}
+
+		logrus.Warnf("%v, retrying on another port", err)
+	}
+
+	return nil, err
+}
+
+func startStream(ctx context.Context, stream service.Stream) (
 	*service.StartStreamInfo, error) {
 	streamLaunchInfo, err := stream.Start(ctx)
 	if err != nil {
@@ -585,7 +815,7 @@ func connectToStream(address string, tryCount int) error {
 	return errors.New("connection timeout")
 }
 
-func buildStreamOwnerInfo(info *streamInfo, accessToken string) *service.StreamOwnerInfo {
+func buildStreamOwnerInfo(info *streamInfo, auth *service.AuthInfo) *service.StreamOwnerInfo {
 	ownerInfo := service.StreamOwnerInfo{
 		UUID:        info.UUID,
 		Name:        info.Name,
@@ -601,15 +831,30 @@ func buildStreamOwnerInfo(info *streamInfo, accessToken string) *service.StreamO
 			AvatarID: info.Host.AvatarID,
 			IP:       info.Host.IP,
 		},
-		StartedAt: info.StartedAt,
-	}
-
-	if accessToken != "" {
-		ownerInfo.Auth = &service.AuthInfo{
-			AccessToken: accessToken,
-			Type:        defaultStreamTokenType,
-		}
+		StartedAt:   info.StartedAt,
+		ScheduledAt: info.ScheduledAt,
+		Auth:        auth,
 	}
 
 	return &ownerInfo
 }
+
+func streamLaunchConfig(info *streamInfo) service.StreamLaunchConfig {
+	return service.StreamLaunchConfig{
+		PreferredPort: info.Preferred.Port,
+		PreferredIP:   info.Preferred.IP,
+		Mode:          info.LaunchMode,
+		Restart: service.StreamRestartConfig{
+			Policy:      info.Restart.Policy,
+			MaxAttempts: info.Restart.MaxAttempts,
+		},
+		Limits: service.StreamResourceLimits{
+			CPU:    info.Limits.CPU,
+			Memory: info.Limits.Memory,
+			PIDs:   info.Limits.PIDs,
+		},
+		Env:   info.Env,
+		Args:  info.Args,
+		Image: info.Image,
+	}
+}
@@ -4,11 +4,16 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/code-cord/cc.core.server/cli"
 	"github.com/code-cord/cc.core.server/service"
 )
 
+const (
+	defaultStreamHandlerTimeout = 10 * time.Second
+)
+
 // StreamHandler represents stream handler implementation mnodel.
 type StreamHandler struct {
 	streamAddress string
@@ -18,7 +23,9 @@ type StreamHandler struct {
 // NewStreamHandler returns new stream handler instance.
 func NewStreamHandler(serveAddress string) *StreamHandler {
 	return &StreamHandler{
-		httpClient:    http.DefaultClient,
+		httpClient: &http.Client{
+			Timeout: defaultStreamHandlerTimeout,
+		},
 		streamAddress: fmt.Sprintf("http://%s", serveAddress),
 	}
 }
@@ -46,3 +53,27 @@ func (h *StreamHandler) ChangeParticipantInfo(p service.StreamParticipant) error
 		ExpStatusCode: http.StatusOK,
 	})
 }
+
+// RemoveParticipant reports stream about removing participant.
+func (h *StreamHandler) RemoveParticipant(p service.StreamParticipant) error {
+	return cli.DoRequest(context.Background(), cli.RequestParams{
+		Client:        h.httpClient,
+		BasePath:      "/participant",
+		BaseAddress:   h.streamAddress,
+		Method:        http.MethodDelete,
+		Body:          p,
+		ExpStatusCode: http.StatusOK,
+	})
+}
+
+// SendEvent sends server event to the stream.
+func (h *StreamHandler) SendEvent(e service.StreamEvent) error {
+	return cli.DoRequest(context.Background(), cli.RequestParams{
+		Client:        h.httpClient,
+		BasePath:      "/event",
+		BaseAddress:   h.streamAddress,
+		Method:        http.MethodPost,
+		Body:          e,
+		ExpStatusCode: http.StatusOK,
+	})
+}
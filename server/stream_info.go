@@ -0,0 +1,46 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// lockStreamInfo locks writes of the stream info and returns the func to unlock them.
+func (s *Server) lockStreamInfo(streamUUID string) func() {
+	lock, _ := s.infoLocks.LoadOrStore(streamUUID, new(sync.Mutex))
+	mu := lock.(*sync.Mutex)
+	mu.Lock()
+
+	return mu.Unlock
+}
+
+// updateStreamInfo applies the update to the stored info of the stream served by the server.
+//
+// Updates are serialized per stream and applied to the freshly loaded info. If the stream
+// isn't served anymore, errStreamFinished is returned and nothing is written, so the info
+// of the finished stream is never brought back.
+func (s *Server) updateStreamInfo(streamUUID string, update func(info *streamInfo) error) (
+	*streamInfo, error) {
+	unlock := s.lockStreamInfo(streamUUID)
+	defer unlock()
+
+	if _, ok := s.streams.Load(streamUUID); !ok {
+		return nil, errStreamFinished
+	}
+
+	info, err := s.loadStreamInfo(streamUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := update(info); err != nil {
+		return nil, err
+	}
+
+	if err := s.streamStorage.Default().Store(streamUUID, info, json.Marshal); err != nil {
+		return nil, fmt.Errorf("could not store %s stream data: %v", streamUUID, err)
+	}
+
+	return info, nil
+}
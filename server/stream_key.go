@@ -0,0 +1,167 @@
+package server
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"runtime"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultMasterKeyFileName = "master.key"
+	defaultMasterKeySize     = 32
+	masterKeyFileMode        = 0600
+)
+
+// streamKeys returns access keys of the stream.
+//
+// Keys are loaded from the storage on first access and cached afterwards.
+func (s *Server) streamKeys(streamUUID string) (*rsaKeys, error) {
+	if keys, ok := s.keys.Load(streamUUID); ok {
+		return keys.(*rsaKeys), nil
+	}
+
+	keyRV := s.streamStorage.Use(streamKeyBucket).Load(streamUUID)
+	if keyRV == nil {
+		return nil, fmt.Errorf("could not find access keys of the %s stream", streamUUID)
+	}
+
+	data, err := decryptData(s.opts.masterKey, keyRV.Get())
+	if err != nil {
+		return nil, fmt.Errorf("could not decrypt stream access keys: %v", err)
+	}
+
+	keys, err := decodeRSAKeys(data)
+	if err != nil {
+		return nil, err
+	}
+	s.keys.Store(streamUUID, keys)
+
+	return keys, nil
+}
+
+func (s *Server) storeStreamKeys(streamUUID string, keys *rsaKeys) error {
+	data, err := encryptData(s.opts.masterKey, encodeRSAKeys(keys))
+	if err != nil {
+		return fmt.Errorf("could not encrypt stream access keys: %v", err)
+	}
+
+	if err := s.streamStorage.Use(streamKeyBucket).Store(streamUUID, data, nil); err != nil {
+		return err
+	}
+	s.keys.Store(streamUUID, keys)
+
+	return nil
+}
+
+func (s *Server) deleteStreamKeys(streamUUID string) error {
+	s.keys.Delete(streamUUID)
+
+	return s.streamStorage.Use(streamKeyBucket).Delete(streamUUID)
+}
+
+func encodeRSAKeys(keys *rsaKeys) []byte {
+	return pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(keys.privateKey),
+	})
+}
+
+func decodeRSAKeys(data []byte) (*rsaKeys, error) {
+	privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(data)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse private RSA key: %v", err)
+	}
+
+	return &rsaKeys{
+		privateKey: privateKey,
+		publicKey:  &privateKey.PublicKey,
+	}, nil
+}
+
+func encryptData(key, data []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("could not generate nonce: %v", err)
+	}
+
+	return gcm.Seal(nonce, nonce, data, nil), nil
+}
+
+func decryptData(key, data []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonceSize := gcm.NonceSize()
+	if len(data) < nonceSize {
+		return nil, errors.New("encrypted data is too short")
+	}
+
+	return gcm.Open(nil, data[:nonceSize], data[nonceSize:], nil)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	if len(key) == 0 {
+		return nil, errors.New("master key is not set")
+	}
+
+	secret := sha256.Sum256(key)
+	block, err := aes.NewCipher(secret[:])
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+func loadMasterKey(keyPath string) ([]byte, error) {
+	key, err := ioutil.ReadFile(keyPath)
+	if err == nil {
+		if len(key) == 0 {
+			return nil, fmt.Errorf("master key file %s is empty", keyPath)
+		}
+
+		// the key must only be readable by the server user, but the file is owned
+		// by the operator, so its mode is left as is.
+		if info, err := os.Stat(keyPath); err == nil && runtime.GOOS != "windows" &&
+			info.Mode().Perm()&^masterKeyFileMode != 0 {
+			logrus.Warnf("master key file %s is accessible by other users (mode %v), it should be %v",
+				keyPath, info.Mode().Perm(), os.FileMode(masterKeyFileMode))
+		}
+
+		return key, nil
+	}
+
+	if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("could not read %s master key file: %v", keyPath, err)
+	}
+
+	key = make([]byte, defaultMasterKeySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, fmt.Errorf("could not generate master key: %v", err)
+	}
+
+	if err := ioutil.WriteFile(keyPath, key, masterKeyFileMode); err != nil {
+		return nil, fmt.Errorf("could not write %s master key file: %v", keyPath, err)
+	}
+
+	return key, nil
+}
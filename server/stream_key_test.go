@@ -0,0 +1,61 @@
+package server
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestLoadMasterKeyKeepsFileMode(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file mode isn't supported on windows")
+	}
+
+	keyPath := filepath.Join(t.TempDir(), defaultMasterKeyFileName)
+	if err := ioutil.WriteFile(keyPath, []byte("operator key"), 0644); err != nil {
+		t.Fatalf("could not write master key file: %v", err)
+	}
+	// umask may restrict the mode on write.
+	if err := os.Chmod(keyPath, 0644); err != nil {
+		t.Fatalf("could not set master key file mode: %v", err)
+	}
+
+	key, err := loadMasterKey(keyPath)
+	if err != nil {
+		t.Fatalf("could not load master key: %v", err)
+	}
+	if !bytes.Equal(key, []byte("operator key")) {
+		t.Errorf("unexpected master key: %q", key)
+	}
+
+	info, err := os.Stat(keyPath)
+	if err != nil {
+		t.Fatalf("could not stat master key file: %v", err)
+	}
+	if info.Mode().Perm() != 0644 {
+		t.Errorf("master key file mode is changed to %v", info.Mode().Perm())
+	}
+}
+
+func TestLoadMasterKeyGeneratesKey(t *testing.T) {
+	keyPath := filepath.Join(t.TempDir(), defaultMasterKeyFileName)
+
+	key, err := loadMasterKey(keyPath)
+	if err != nil {
+		t.Fatalf("could not generate master key: %v", err)
+	}
+	if len(key) != defaultMasterKeySize {
+		t.Errorf("master key size is %d, want %d", len(key), defaultMasterKeySize)
+	}
+
+	loaded, err := loadMasterKey(keyPath)
+	if err != nil {
+		t.Fatalf("could not load master key: %v", err)
+	}
+	if !bytes.Equal(key, loaded) {
+		t.Error("loaded master key differs from the generated one")
+	}
+}
@@ -0,0 +1,264 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultStreamLogFolder       = "logs"
+	defaultStreamLogMaxSize      = 10 << 20
+	defaultStreamLogMaxBackups   = 3
+	defaultStreamLogPollInterval = 500 * time.Millisecond
+	logFileExt                   = ".log"
+)
+
+// streamLogFile represents stream log file which is rotated once it reaches
+// the max size.
+type streamLogFile struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+	mu         sync.Mutex
+	file       *os.File
+	size       int64
+	closed     bool
+}
+
+// streamLogReader represents reader of the stream log file which optionally
+// follows the file while the stream is running.
+type streamLogReader struct {
+	ctx     context.Context
+	path    string
+	follow  bool
+	running func() bool
+	file    *os.File
+}
+
+// StreamLogs returns reader of the stream output log.
+//
+// If follow is set, the reader waits for new output until the stream is
+// finished or the ctx is done.
+func (s *Server) StreamLogs(ctx context.Context, streamUUID string, follow bool) (
+	io.ReadCloser, error) {
+	if streamRV := s.streamStorage.Default().Load(streamUUID); streamRV == nil {
+		return nil, os.ErrNotExist
+	}
+
+	return &streamLogReader{
+		ctx:    ctx,
+		path:   s.streamLogPath(streamUUID),
+		follow: follow,
+		running: func() bool {
+			_, ok := s.streams.Load(streamUUID)
+			return ok
+		},
+	}, nil
+}
+
+// captureStreamLogs writes output of the current stream run to the stream log file.
+func (s *Server) captureStreamLogs(streamUUID string, module *streamModule) {
+	logs, err := module.Logs(context.Background())
+	if err != nil {
+		logrus.Warnf("could not capture %s stream logs: %v", streamUUID, err)
+		return
+	}
+	defer logs.Close()
+
+	if err := copyStreamLogs(module.logs, logs); err != nil && err != os.ErrClosed {
+		logrus.Errorf("could not write %s stream logs: %v", streamUUID, err)
+	}
+}
+
+// copyStreamLogs copies the stream output to the log.
+//
+// If the log can't be written, the output is still drained until the stream closes it,
+// so the stream is never blocked on writing it.
+func copyStreamLogs(w io.Writer, r io.Reader) error {
+	_, err := io.Copy(w, r)
+	if err != nil {
+		io.Copy(io.Discard, r)
+	}
+
+	return err
+}
+
+func (s *Server) newStreamLogFile(streamUUID string) *streamLogFile {
+	maxSize := s.opts.StreamLogMaxSize
+	if maxSize <= 0 {
+		maxSize = defaultStreamLogMaxSize
+	}
+
+	return &streamLogFile{
+		path:       s.streamLogPath(streamUUID),
+		maxSize:    maxSize,
+		maxBackups: defaultStreamLogMaxBackups,
+	}
+}
+
+func (s *Server) streamLogPath(streamUUID string) string {
+	return path.Join(s.opts.DataFolder, defaultStreamLogFolder, streamUUID+logFileExt)
+}
+
+// Write writes data to the log file, rotating it if needed.
+func (f *streamLogFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		return 0, os.ErrClosed
+	}
+
+	if f.file == nil {
+		if err := f.open(); err != nil {
+			return 0, err
+		}
+	}
+
+	if f.size > 0 && f.size+int64(len(p)) > f.maxSize {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+
+	return n, err
+}
+
+// Close closes the log file. Any further write fails.
+func (f *streamLogFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.closed = true
+	if f.file == nil {
+		return nil
+	}
+
+	return f.file.Close()
+}
+
+func (f *streamLogFile) open() error {
+	if err := os.MkdirAll(path.Dir(f.path), 0700); err != nil {
+		return fmt.Errorf("could not create log folder: %v", err)
+	}
+
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("could not open %s log file: %v", f.path, err)
+	}
+
+	fileInfo, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("could not read %s log file info: %v", f.path, err)
+	}
+
+	f.file = file
+	f.size = fileInfo.Size()
+
+	return nil
+}
+
+// rotate shifts the log file backups and starts a new log file.
+func (f *streamLogFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return fmt.Errorf("could not close %s log file: %v", f.path, err)
+	}
+	f.file = nil
+
+	for i := f.maxBackups - 1; i > 0; i-- {
+		backupPath := fmt.Sprintf("%s.%d", f.path, i)
+		err := os.Rename(backupPath, fmt.Sprintf("%s.%d", f.path, i+1))
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("could not rotate %s log file: %v", backupPath, err)
+		}
+	}
+
+	if err := os.Rename(f.path, f.path+".1"); err != nil {
+		return fmt.Errorf("could not rotate %s log file: %v", f.path, err)
+	}
+
+	return f.open()
+}
+
+// Read reads the stream log.
+//
+// In follow mode it handles rotation of the log file by reopening it once
+// the rotated file has been read to the end.
+func (r *streamLogReader) Read(p []byte) (int, error) {
+	for {
+		if r.file == nil {
+			file, err := os.Open(r.path)
+			if err != nil && !os.IsNotExist(err) {
+				return 0, err
+			}
+
+			if err == nil {
+				r.file = file
+				continue
+			}
+
+			if !r.follow || !r.running() {
+				return 0, io.EOF
+			}
+		} else {
+			n, err := r.file.Read(p)
+			if n > 0 || err != io.EOF {
+				return n, err
+			}
+
+			if !r.follow {
+				return 0, io.EOF
+			}
+
+			if r.rotated() {
+				r.file.Close()
+				r.file = nil
+				continue
+			}
+
+			if !r.running() {
+				return 0, io.EOF
+			}
+		}
+
+		select {
+		case <-time.After(defaultStreamLogPollInterval):
+		case <-r.ctx.Done():
+			return 0, r.ctx.Err()
+		}
+	}
+}
+
+// Close closes the stream log reader.
+func (r *streamLogReader) Close() error {
+	if r.file == nil {
+		return nil
+	}
+
+	return r.file.Close()
+}
+
+func (r *streamLogReader) rotated() bool {
+	currentInfo, err := r.file.Stat()
+	if err != nil {
+		return false
+	}
+
+	pathInfo, err := os.Stat(r.path)
+	if err != nil {
+		return false
+	}
+
+	return !os.SameFile(currentInfo, pathInfo)
+}
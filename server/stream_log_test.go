@@ -0,0 +1,45 @@
+package server
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// failingWriter represents log which can't be written, e.g. on a full disk.
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("no space left on device")
+}
+
+func TestCopyStreamLogsDrainsOutput(t *testing.T) {
+	r, w := io.Pipe()
+
+	written := make(chan error, 1)
+	go func() {
+		// the stream keeps writing after the log fails.
+		for i := 0; i < 100; i++ {
+			if _, err := io.WriteString(w, strings.Repeat("x", 1024)); err != nil {
+				written <- err
+				return
+			}
+		}
+		written <- w.Close()
+	}()
+
+	if err := copyStreamLogs(failingWriter{}, r); err == nil {
+		t.Error("log write error isn't reported")
+	}
+
+	select {
+	case err := <-written:
+		if err != nil {
+			t.Errorf("could not write stream output: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("stream output isn't drained after the log write error")
+	}
+}
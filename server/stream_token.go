@@ -0,0 +1,206 @@
+package server
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/code-cord/cc.core.server/service"
+	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultStreamAccessTokenTTL  = 15 * time.Minute
+	defaultStreamRefreshTokenTTL = 24 * time.Hour
+	streamTokenTypeClaim         = "typ"
+	streamTokenTypeAccess        = "access"
+	streamTokenTypeRefresh       = "refresh"
+)
+
+// revokedStreamTokens represents revoked tokens of the stream by JTI along with
+// their expiration time.
+type revokedStreamTokens map[string]time.Time
+
+// RefreshStreamToken issues a new pair of the stream tokens in exchange for the refresh token.
+//
+// Refresh tokens are single use, the exchanged token is revoked.
+func (s *Server) RefreshStreamToken(ctx context.Context, streamUUID, refreshToken string) (
+	*service.AuthInfo, error) {
+	keys, err := s.streamKeys(streamUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := parseStreamToken(refreshToken, &keys.privateKey.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid refresh token: %v", err)
+	}
+
+	if claims[streamTokenTypeClaim] != streamTokenTypeRefresh {
+		return nil, errors.New("invalid refresh token: unexpected token type")
+	}
+
+	if claims["streamUUID"] != streamUUID {
+		return nil, errors.New("invalid refresh token: token is issued for another stream")
+	}
+
+	// the role of the participant may have been changed since the token was issued.
+	participantUUID, _ := claims["UUID"].(string)
+	p, err := s.Participant(ctx, streamUUID, participantUUID)
+	if err != nil || p.Status != service.ParticipantStatusActive {
+		return nil, errors.New("participant has no access to the stream")
+	}
+
+	jti, _ := claims["jti"].(string)
+	expiresAt, _ := claims["exp"].(float64)
+	if err := s.revokeStreamToken(streamUUID, jti, time.Unix(int64(expiresAt), 0)); err != nil {
+		return nil, err
+	}
+
+	return s.generateStreamTokens(streamUUID, participantUUID, p.Role, keys.privateKey)
+}
+
+// StreamTokenRevoked reports whether the stream token with JTI has been revoked.
+func (s *Server) StreamTokenRevoked(ctx context.Context, streamUUID, jti string) (bool, error) {
+	access, err := s.streamAccess(streamUUID)
+	if err != nil {
+		return false, err
+	}
+
+	_, ok := access.revoked[jti]
+
+	return ok, nil
+}
+
+// generateStreamTokens returns short-lived access token along with the refresh token
+// of the stream participant.
+func (s *Server) generateStreamTokens(streamUUID, participantUUID string,
+	role service.ParticipantRole, privateKey *rsa.PrivateKey) (*service.AuthInfo, error) {
+	isHost := role == service.ParticipantRoleHost
+	now := time.Now()
+	accessExpiresAt := now.Add(s.opts.StreamAccessTokenTTL)
+	accessToken, err := signStreamToken(jwt.MapClaims{
+		"streamUUID":         streamUUID,
+		"UUID":               participantUUID,
+		"host":               isHost,
+		"role":               role,
+		"iat":                now.Unix(),
+		"exp":                accessExpiresAt.Unix(),
+		"jti":                uuid.New().String(),
+		streamTokenTypeClaim: streamTokenTypeAccess,
+	}, privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := signStreamToken(jwt.MapClaims{
+		"streamUUID":         streamUUID,
+		"UUID":               participantUUID,
+		"host":               isHost,
+		"role":               role,
+		"iat":                now.Unix(),
+		"exp":                now.Add(s.opts.StreamRefreshTokenTTL).Unix(),
+		"jti":                uuid.New().String(),
+		streamTokenTypeClaim: streamTokenTypeRefresh,
+	}, privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &service.AuthInfo{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresAt:    accessExpiresAt.UTC(),
+		Type:         defaultStreamTokenType,
+	}, nil
+}
+
+func (s *Server) revokeStreamToken(streamUUID, jti string, expiresAt time.Time) error {
+	if jti == "" {
+		return errors.New("token has no JTI")
+	}
+
+	s.revokedMu.Lock()
+	defer s.revokedMu.Unlock()
+
+	revoked, err := s.revokedStreamTokens(streamUUID)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := revoked[jti]; ok {
+		return errors.New("token is revoked")
+	}
+
+	// expired tokens are rejected anyway.
+	now := time.Now()
+	for revokedJTI, revokedExpiresAt := range revoked {
+		if revokedExpiresAt.Before(now) {
+			delete(revoked, revokedJTI)
+		}
+	}
+	revoked[jti] = expiresAt
+
+	if err := s.streamStorage.Use(revokedTokenBucket).
+		Store(streamUUID, revoked, json.Marshal); err != nil {
+		return fmt.Errorf("could not store %s stream revoked tokens: %v", streamUUID, err)
+	}
+	s.access.invalidate(streamUUID)
+
+	return nil
+}
+
+func (s *Server) revokedStreamTokens(streamUUID string) (revokedStreamTokens, error) {
+	revoked := make(revokedStreamTokens)
+
+	revokedRV := s.streamStorage.Use(revokedTokenBucket).Load(streamUUID)
+	if revokedRV == nil {
+		return revoked, nil
+	}
+
+	if err := revokedRV.Decode(&revoked, json.Unmarshal); err != nil {
+		return nil, fmt.Errorf("could not decode revoked tokens data: %v", err)
+	}
+
+	return revoked, nil
+}
+
+func (s *Server) deleteRevokedStreamTokens(streamUUID string) error {
+	defer s.access.invalidate(streamUUID)
+
+	return s.streamStorage.Use(revokedTokenBucket).Delete(streamUUID)
+}
+
+func signStreamToken(claims jwt.MapClaims, privateKey *rsa.PrivateKey) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	return token.SignedString(privateKey)
+}
+
+// parseStreamToken parses the stream token and validates its signature and expiration.
+func parseStreamToken(tokenStr string, publicKey *rsa.PublicKey) (jwt.MapClaims, error) {
+	token, err := jwt.Parse(tokenStr, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, fmt.Errorf("unexpected method: %s", t.Header["alg"])
+		}
+
+		return publicKey, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return nil, errors.New("invalid token")
+	}
+
+	if !claims.VerifyExpiresAt(time.Now().Unix(), true) {
+		return nil, errors.New("token has no expiration time")
+	}
+
+	return claims, nil
+}
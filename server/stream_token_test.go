@@ -0,0 +1,118 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/code-cord/cc.core.server/service"
+)
+
+func TestRefreshStreamToken(t *testing.T) {
+	s := newTestServer(t)
+	ctx := context.Background()
+
+	stream, err := s.NewStream(ctx, testStreamConfig())
+	if err != nil {
+		t.Fatalf("could not create stream: %v", err)
+	}
+
+	keys, err := s.streamKeys(stream.UUID)
+	if err != nil {
+		t.Fatalf("could not get stream keys: %v", err)
+	}
+	refreshClaims, err := parseStreamToken(stream.Auth.RefreshToken, keys.publicKey)
+	if err != nil {
+		t.Fatalf("could not parse refresh token: %v", err)
+	}
+	refreshJTI := refreshClaims["jti"].(string)
+
+	// the access token can't be exchanged.
+	if _, err := s.RefreshStreamToken(ctx, stream.UUID, stream.Auth.AccessToken); err == nil {
+		t.Error("access token is exchanged")
+	}
+
+	auth, err := s.RefreshStreamToken(ctx, stream.UUID, stream.Auth.RefreshToken)
+	if err != nil {
+		t.Fatalf("could not refresh token: %v", err)
+	}
+	if auth.AccessToken == "" || auth.RefreshToken == stream.Auth.RefreshToken {
+		t.Errorf("unexpected refreshed tokens: %+v", auth)
+	}
+
+	revoked, err := s.StreamTokenRevoked(ctx, stream.UUID, refreshJTI)
+	if err != nil || !revoked {
+		t.Errorf("exchanged token isn't revoked: %v", err)
+	}
+
+	// refresh tokens are single use.
+	if _, err := s.RefreshStreamToken(ctx, stream.UUID, stream.Auth.RefreshToken); err == nil {
+		t.Error("refresh token is exchanged twice")
+	}
+	if _, err := s.RefreshStreamToken(ctx, stream.UUID, auth.RefreshToken); err != nil {
+		t.Errorf("could not refresh token: %v", err)
+	}
+}
+
+func TestStreamAccessCache(t *testing.T) {
+	s := newTestServer(t)
+	ctx := context.Background()
+
+	stream, err := s.NewStream(ctx, testStreamConfig())
+	if err != nil {
+		t.Fatalf("could not create stream: %v", err)
+	}
+
+	_, err = s.JoinParticipant(ctx, stream.UUID, stream.JoinCode, service.Participant{
+		Name: "participant",
+	})
+	if err != nil {
+		t.Fatalf("could not join participant: %v", err)
+	}
+	participants, err := s.StreamParticipants(ctx, stream.UUID)
+	if err != nil || len(participants) != 1 {
+		t.Fatalf("could not get participants: %v", err)
+	}
+	participantUUID := participants[0].UUID
+
+	if _, err := s.Participant(ctx, stream.UUID, participantUUID); err != nil {
+		t.Fatalf("joined participant isn't found: %v", err)
+	}
+
+	// the cached access is served without loading the participants.
+	if err := s.participantStorage.Default().
+		Store(stream.UUID, []participantInfo{}, json.Marshal); err != nil {
+		t.Fatalf("could not store participants: %v", err)
+	}
+	if _, err := s.Participant(ctx, stream.UUID, participantUUID); err != nil {
+		t.Errorf("cached participant isn't found: %v", err)
+	}
+
+	// the access is reloaded once it's changed through the server.
+	s.access.invalidate(stream.UUID)
+	if _, err := s.Participant(ctx, stream.UUID, participantUUID); err == nil {
+		t.Error("stale participant is served")
+	}
+
+	_, err = s.JoinParticipant(ctx, stream.UUID, stream.JoinCode, service.Participant{
+		Name: "kicked participant",
+	})
+	if err != nil {
+		t.Fatalf("could not join participant: %v", err)
+	}
+	participants, err = s.StreamParticipants(ctx, stream.UUID)
+	if err != nil || len(participants) != 1 {
+		t.Fatalf("could not get participants: %v", err)
+	}
+	kickedUUID := participants[0].UUID
+
+	if _, err := s.Participant(ctx, stream.UUID, kickedUUID); err != nil {
+		t.Fatalf("joined participant isn't found: %v", err)
+	}
+	if err := s.KickParticipant(ctx, stream.UUID, stream.Host.UUID, kickedUUID); err != nil {
+		t.Fatalf("could not kick participant: %v", err)
+	}
+	if _, err := s.Participant(ctx, stream.UUID, kickedUUID); err == nil {
+		t.Error("kicked participant is served from the cache")
+	}
+}
@@ -0,0 +1,234 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/code-cord/cc.core.server/service"
+)
+
+type streamTemplate struct {
+	Name        string                   `json:"name"`
+	Description string                   `json:"desc,omitempty"`
+	Join        streamJoinInfo           `json:"join"`
+	LaunchMode  service.StreamLaunchMode `json:"mode,omitempty"`
+	Preferred   streamPreferredInfo      `json:"preferred"`
+	Restart     streamRestartInfo        `json:"restart"`
+	Limits      streamLimitsInfo         `json:"limits"`
+	MaxDuration time.Duration            `json:"maxDuration,omitempty"`
+	IdleTimeout time.Duration            `json:"idleTimeout,omitempty"`
+	Env         map[string]string        `json:"env,omitempty"`
+	Args        []string                 `json:"args,omitempty"`
+	Image       string                   `json:"image,omitempty"`
+}
+
+// NewStreamTemplate stores a new stream template.
+func (s *Server) NewStreamTemplate(ctx context.Context, tpl service.StreamTemplate) error {
+	if templateRV := s.streamStorage.Use(templateBucket).Load(tpl.Name); templateRV != nil {
+		return fmt.Errorf("template %s already exists", tpl.Name)
+	}
+
+	return s.storeStreamTemplate(tpl)
+}
+
+// StreamTemplates returns list of the stream templates sorted by name.
+func (s *Server) StreamTemplates(ctx context.Context) ([]service.StreamTemplate, error) {
+	cursor, err := s.streamStorage.Use(templateBucket).All()
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch templates from storage: %v", err)
+	}
+	defer cursor.Close()
+
+	templates := make([]service.StreamTemplate, 0)
+	for rv, hasNext := cursor.First(); hasNext; rv, hasNext = cursor.Next() {
+		var tpl streamTemplate
+		if err := rv.Decode(&tpl, json.Unmarshal); err != nil {
+			return nil, fmt.Errorf("could not parse template: %v", err)
+		}
+
+		templates = append(templates, buildStreamTemplate(&tpl))
+	}
+
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].Name < templates[j].Name
+	})
+
+	return templates, nil
+}
+
+// StreamTemplate returns stream template by name.
+func (s *Server) StreamTemplate(ctx context.Context, name string) (
+	*service.StreamTemplate, error) {
+	templateRV := s.streamStorage.Use(templateBucket).Load(name)
+	if templateRV == nil {
+		return nil, os.ErrNotExist
+	}
+
+	var tpl streamTemplate
+	if err := templateRV.Decode(&tpl, json.Unmarshal); err != nil {
+		return nil, fmt.Errorf("could not decode template data: %v", err)
+	}
+
+	template := buildStreamTemplate(&tpl)
+
+	return &template, nil
+}
+
+// UpdateStreamTemplate replaces existing stream template.
+func (s *Server) UpdateStreamTemplate(ctx context.Context, tpl service.StreamTemplate) error {
+	if templateRV := s.streamStorage.Use(templateBucket).Load(tpl.Name); templateRV == nil {
+		return os.ErrNotExist
+	}
+
+	return s.storeStreamTemplate(tpl)
+}
+
+// DeleteStreamTemplate deletes stream template by name.
+func (s *Server) DeleteStreamTemplate(ctx context.Context, name string) error {
+	if templateRV := s.streamStorage.Use(templateBucket).Load(name); templateRV == nil {
+		return os.ErrNotExist
+	}
+
+	return s.streamStorage.Use(templateBucket).Delete(name)
+}
+
+func (s *Server) storeStreamTemplate(tpl service.StreamTemplate) error {
+	if tpl.Launch.Mode != "" {
+		if _, err := s.launchModeFactory(tpl.Launch.Mode); err != nil {
+			return err
+		}
+	}
+
+	if err := s.checkStreamImage(tpl.Launch); err != nil {
+		return err
+	}
+
+	if err := checkStreamEnv(tpl.Launch); err != nil {
+		return err
+	}
+
+	if err := checkStreamArgs(tpl.Launch); err != nil {
+		return err
+	}
+
+	template := streamTemplate{
+		Name:        tpl.Name,
+		Description: tpl.Description,
+		Join: streamJoinInfo{
+			Code:   tpl.Join.JoinCode,
+			Policy: tpl.Join.JoinPolicy,
+		},
+		LaunchMode: tpl.Launch.Mode,
+		Preferred: streamPreferredInfo{
+			IP:   tpl.Launch.PreferredIP,
+			Port: tpl.Launch.PreferredPort,
+		},
+		Restart: streamRestartInfo{
+			Policy:      tpl.Launch.Restart.Policy,
+			MaxAttempts: tpl.Launch.Restart.MaxAttempts,
+		},
+		Limits: streamLimitsInfo{
+			CPU:    tpl.Launch.Limits.CPU,
+			Memory: tpl.Launch.Limits.Memory,
+			PIDs:   tpl.Launch.Limits.PIDs,
+		},
+		MaxDuration: tpl.MaxDuration,
+		IdleTimeout: tpl.IdleTimeout,
+		Env:         tpl.Launch.Env,
+		Args:        tpl.Launch.Args,
+		Image:       tpl.Launch.Image,
+	}
+
+	if err := s.streamStorage.Use(templateBucket).Store(tpl.Name, template, json.Marshal); err != nil {
+		return fmt.Errorf("could not store %s template: %v", tpl.Name, err)
+	}
+
+	return nil
+}
+
+// applyStreamTemplate fills in the stream configuration values which are not set
+// with the values of the template.
+func applyStreamTemplate(cfg service.StreamConfig, tpl *service.StreamTemplate) service.StreamConfig {
+	if cfg.Description == "" {
+		cfg.Description = tpl.Description
+	}
+
+	if cfg.Join.JoinPolicy == "" {
+		cfg.Join = tpl.Join
+	}
+
+	launch := &cfg.Launch
+	if launch.Mode == "" {
+		launch.Mode = tpl.Launch.Mode
+	}
+	if launch.PreferredIP == "" {
+		launch.PreferredIP = tpl.Launch.PreferredIP
+	}
+	if launch.PreferredPort == 0 {
+		launch.PreferredPort = tpl.Launch.PreferredPort
+	}
+	if launch.Restart.Policy == "" {
+		launch.Restart = tpl.Launch.Restart
+	}
+	if launch.Limits.CPU == 0 {
+		launch.Limits.CPU = tpl.Launch.Limits.CPU
+	}
+	if launch.Limits.Memory == 0 {
+		launch.Limits.Memory = tpl.Launch.Limits.Memory
+	}
+	if launch.Limits.PIDs == 0 {
+		launch.Limits.PIDs = tpl.Launch.Limits.PIDs
+	}
+	if launch.Env == nil {
+		launch.Env = tpl.Launch.Env
+	}
+	if launch.Args == nil {
+		launch.Args = tpl.Launch.Args
+	}
+	if launch.Image == "" {
+		launch.Image = tpl.Launch.Image
+	}
+
+	if cfg.MaxDuration == 0 {
+		cfg.MaxDuration = tpl.MaxDuration
+	}
+	if cfg.IdleTimeout == 0 {
+		cfg.IdleTimeout = tpl.IdleTimeout
+	}
+
+	return cfg
+}
+
+func buildStreamTemplate(tpl *streamTemplate) service.StreamTemplate {
+	return service.StreamTemplate{
+		Name:        tpl.Name,
+		Description: tpl.Description,
+		Join: service.StreamJoinPolicyConfig{
+			JoinPolicy: tpl.Join.Policy,
+			JoinCode:   tpl.Join.Code,
+		},
+		Launch: service.StreamLaunchConfig{
+			PreferredPort: tpl.Preferred.Port,
+			PreferredIP:   tpl.Preferred.IP,
+			Mode:          tpl.LaunchMode,
+			Restart: service.StreamRestartConfig{
+				Policy:      tpl.Restart.Policy,
+				MaxAttempts: tpl.Restart.MaxAttempts,
+			},
+			Limits: service.StreamResourceLimits{
+				CPU:    tpl.Limits.CPU,
+				Memory: tpl.Limits.Memory,
+				PIDs:   tpl.Limits.PIDs,
+			},
+			Env:   tpl.Env,
+			Args:  tpl.Args,
+			Image: tpl.Image,
+		},
+		MaxDuration: tpl.MaxDuration,
+		IdleTimeout: tpl.IdleTimeout,
+	}
+}
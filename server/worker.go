@@ -0,0 +1,149 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/code-cord/cc.core.server/cli"
+	"github.com/code-cord/cc.core.server/service"
+	"github.com/code-cord/cc.core.server/stream"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultWorkerPlacementTimeout = 5 * time.Second
+)
+
+func newRemoteWorkerStream(cfg LaunchModeConfig) (service.Stream, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultWorkerPlacementTimeout)
+	defer cancel()
+
+	workerAddresses, err := placeStream(ctx, &cfg.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	// the stream is started on the next worker if the previous one gets full.
+	fallbackWorkers := make([]cli.WorkerClientConfig, 0, len(workerAddresses)-1)
+	for _, address := range workerAddresses[1:] {
+		fallbackWorkers = append(fallbackWorkers, cfg.Options.workerClientConfig(address))
+	}
+
+	return stream.NewRemoteWorkerStream(stream.RemoteWorkerStreamConfig{
+		StreamUUID:      cfg.StreamUUID,
+		Worker:          cfg.Options.workerClientConfig(workerAddresses[0]),
+		FallbackWorkers: fallbackWorkers,
+		PreferedIP:      cfg.Launch.PreferredIP,
+		PreferedPort:    cfg.Launch.PreferredPort,
+		Limits:          cfg.Launch.Limits,
+		Env:             cfg.Launch.Env,
+		Args:            cfg.Launch.Args,
+		Image:           cfg.Launch.Image,
+	}), nil
+}
+
+// placeStream returns addresses of the workers able to run one more stream, starting
+// from the least loaded one.
+//
+// Load of the worker is the ratio of its running streams to its capacity. Workers
+// with unlimited capacity are compared by the number of running streams.
+func placeStream(ctx context.Context, opts *Options) ([]string, error) {
+	workers := opts.Workers
+	if len(workers) == 0 {
+		return nil, errors.New("no remote workers are configured")
+	}
+
+	type workerLoad struct {
+		address string
+		load    float64
+		streams int
+	}
+
+	candidates := make([]workerLoad, 0, len(workers))
+	for _, address := range workers {
+		client := cli.NewWorkerClient(opts.workerClientConfig(address))
+		info, err := client.Info(ctx)
+		if err != nil {
+			logrus.Warnf("could not fetch %s worker info: %v", address, err)
+			continue
+		}
+
+		if info.Capacity > 0 && info.Streams >= info.Capacity {
+			continue
+		}
+
+		var load float64
+		if info.Capacity > 0 {
+			load = float64(info.Streams) / float64(info.Capacity)
+		}
+
+		candidates = append(candidates, workerLoad{
+			address: address,
+			load:    load,
+			streams: info.Streams,
+		})
+	}
+
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("none of %d remote workers can run the stream", len(workers))
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].load != candidates[j].load {
+			return candidates[i].load < candidates[j].load
+		}
+
+		return candidates[i].streams < candidates[j].streams
+	})
+
+	addresses := make([]string, len(candidates))
+	for i, c := range candidates {
+		addresses[i] = c.address
+	}
+
+	return addresses, nil
+}
+
+// workerClientConfig returns configuration of the client requesting the worker API.
+func (o *Options) workerClientConfig(address string) cli.WorkerClientConfig {
+	return cli.WorkerClientConfig{
+		Address:    address,
+		Token:      o.WorkerToken,
+		TLSEnabled: o.WorkerTLSEnabled,
+		HTTPClient: o.workerHTTPClient,
+	}
+}
+
+// newWorkerHTTPClient returns http client requesting the worker API over TLS.
+func newWorkerHTTPClient(caCertFile string) (*http.Client, error) {
+	tlsConfig := tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+
+	if caCertFile != "" {
+		data, err := ioutil.ReadFile(caCertFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not read CA certificate: %v", err)
+		}
+
+		certPool := x509.NewCertPool()
+		if !certPool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("could not parse %s CA certificate", caCertFile)
+		}
+		tlsConfig.RootCAs = certPool
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tlsConfig
+
+	return &http.Client{
+		Transport: transport,
+	}, nil
+}
@@ -0,0 +1,211 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	workerhandler "github.com/code-cord/cc.core.server/handler/worker"
+	"github.com/code-cord/cc.core.server/service"
+)
+
+const testWorkerToken = "worker-token"
+
+// testWorker represents worker reporting its load only.
+type testWorker struct {
+	capacity int
+	streams  int
+}
+
+func (w *testWorker) Info() service.WorkerInfo {
+	return service.WorkerInfo{
+		Name:       "test worker",
+		LaunchMode: service.StreamLaunchModeStandaloneApp,
+		Capacity:   w.capacity,
+		Streams:    w.streams,
+	}
+}
+
+func (w *testWorker) StartStream(ctx context.Context, streamUUID string, cfg service.StreamLaunchConfig) (
+	*service.StartStreamInfo, error) {
+	return nil, os.ErrPermission
+}
+
+func (w *testWorker) WorkerStream(ctx context.Context, streamUUID string) (*service.StartStreamInfo, error) {
+	return nil, os.ErrNotExist
+}
+
+func (w *testWorker) StopStream(ctx context.Context, streamUUID string) error {
+	return os.ErrNotExist
+}
+
+func (w *testWorker) PauseStream(ctx context.Context, streamUUID string) error {
+	return os.ErrNotExist
+}
+
+func (w *testWorker) ResumeStream(ctx context.Context, streamUUID string) error {
+	return os.ErrNotExist
+}
+
+func (w *testWorker) StreamLogs(ctx context.Context, streamUUID string) (io.ReadCloser, error) {
+	return nil, os.ErrNotExist
+}
+
+func (w *testWorker) WaitStream(ctx context.Context, streamUUID string) (*service.WorkerStreamExit, error) {
+	return nil, os.ErrNotExist
+}
+
+// startTestWorkers starts local workers and returns their addresses.
+//
+// The worker is left unreachable if its load is nil.
+func startTestWorkers(t *testing.T, workers ...*testWorker) []string {
+	t.Helper()
+
+	addresses := make([]string, len(workers))
+	for i, w := range workers {
+		srv := httptest.NewServer(workerhandler.New(workerhandler.Config{
+			Worker: w,
+			Token:  testWorkerToken,
+		}))
+		addresses[i] = strings.TrimPrefix(srv.URL, "http://")
+
+		if w == nil {
+			srv.Close()
+			continue
+		}
+		t.Cleanup(srv.Close)
+	}
+
+	return addresses
+}
+
+func TestPlaceStream(t *testing.T) {
+	tests := []struct {
+		name    string
+		workers []*testWorker
+		token   string
+		want    []int
+		wantErr bool
+	}{
+		{
+			name: "least loaded worker",
+			workers: []*testWorker{
+				{capacity: 2, streams: 1},
+				{capacity: 4, streams: 1},
+				{capacity: 3, streams: 2},
+			},
+			want: []int{1, 0, 2},
+		},
+		{
+			name: "full worker is skipped",
+			workers: []*testWorker{
+				{capacity: 1, streams: 1},
+				{capacity: 0, streams: 5},
+			},
+			want: []int{1},
+		},
+		{
+			name: "unlimited workers are compared by streams",
+			workers: []*testWorker{
+				{capacity: 0, streams: 3},
+				{capacity: 0, streams: 1},
+				{capacity: 0, streams: 2},
+			},
+			want: []int{1, 2, 0},
+		},
+		{
+			name: "unreachable worker is skipped",
+			workers: []*testWorker{
+				nil,
+				{capacity: 2, streams: 1},
+			},
+			want: []int{1},
+		},
+		{
+			name: "all workers are full",
+			workers: []*testWorker{
+				{capacity: 1, streams: 1},
+				{capacity: 2, streams: 2},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid worker token",
+			workers: []*testWorker{
+				{capacity: 1, streams: 0},
+			},
+			token:   "invalid-token",
+			wantErr: true,
+		},
+		{
+			name:    "no workers",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addresses := startTestWorkers(t, tt.workers...)
+			token := tt.token
+			if token == "" {
+				token = testWorkerToken
+			}
+
+			placed, err := placeStream(context.Background(), &Options{
+				Workers:     addresses,
+				WorkerToken: token,
+			})
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("stream is placed on %v workers", placed)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("could not place stream: %v", err)
+			}
+
+			want := make([]string, len(tt.want))
+			for i, w := range tt.want {
+				want[i] = addresses[w]
+			}
+			if !reflect.DeepEqual(placed, want) {
+				t.Errorf("stream is placed on %v workers, want %v", placed, want)
+			}
+		})
+	}
+}
+
+func TestPlaceStreamTLS(t *testing.T) {
+	srv := httptest.NewTLSServer(workerhandler.New(workerhandler.Config{
+		Worker: &testWorker{capacity: 1},
+		Token:  testWorkerToken,
+	}))
+	defer srv.Close()
+
+	address := strings.TrimPrefix(srv.URL, "https://")
+	opts := Options{
+		Workers:          []string{address},
+		WorkerToken:      testWorkerToken,
+		WorkerTLSEnabled: true,
+		workerHTTPClient: srv.Client(),
+	}
+
+	placed, err := placeStream(context.Background(), &opts)
+	if err != nil {
+		t.Fatalf("could not place stream: %v", err)
+	}
+	if len(placed) != 1 || placed[0] != address {
+		t.Errorf("stream is placed on %v workers, want %s", placed, address)
+	}
+
+	// the worker isn't requested over plain http.
+	opts.WorkerTLSEnabled = false
+	if _, err := placeStream(context.Background(), &opts); err == nil {
+		t.Error("TLS worker is requested over http")
+	}
+}
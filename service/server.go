@@ -16,6 +16,21 @@ const (
 	ParticipantStatusPending ParticipantStatus = "pending"
 )
 
+// Participant role.
+const (
+	ParticipantRoleHost   ParticipantRole = "host"
+	ParticipantRoleCoHost ParticipantRole = "co-host"
+	ParticipantRoleEditor ParticipantRole = "editor"
+	ParticipantRoleViewer ParticipantRole = "viewer"
+)
+
+// Join request status.
+const (
+	JoinStatusAllowed  JoinStatus = "allowed"
+	JoinStatusRejected JoinStatus = "rejected"
+	JoinStatusExpired  JoinStatus = "expired"
+)
+
 // Stream sort field.
 const (
 	StreamSortByFieldUUID       StreamSortByField = "uuid"
@@ -50,8 +65,18 @@ type Server interface {
 	DecideParticipantJoin(
 		ctx context.Context, streamUUID, participantUUID string, joinAllowed bool) error
 	StreamParticipants(ctx context.Context, streamUUID string) ([]Participant, error)
+	Participant(ctx context.Context, streamUUID, participantUUID string) (*Participant, error)
+	KickParticipant(ctx context.Context, streamUUID, actorUUID, participantUUID string) error
+	BanParticipant(ctx context.Context, streamUUID, actorUUID, participantUUID string) error
+	ChangeParticipantRole(ctx context.Context,
+		streamUUID, participantUUID string, role ParticipantRole) (*Participant, error)
+	TransferStreamHost(ctx context.Context, streamUUID, participantUUID string) (*AuthInfo, error)
 	FinishStream(ctx context.Context, streamUUID string) error
+	PauseStream(ctx context.Context, streamUUID string) error
+	ResumeStream(ctx context.Context, streamUUID string) error
 	NewStreamHostToken(ctx context.Context, streamUUID, subject string) (*AuthInfo, error)
+	RefreshStreamToken(ctx context.Context, streamUUID, refreshToken string) (*AuthInfo, error)
+	StreamTokenRevoked(ctx context.Context, streamUUID, jti string) (bool, error)
 	NewServerToken(ctx context.Context, claims *jwt.StandardClaims) (*AuthInfo, error)
 	StreamKey(ctx context.Context, streamUUID string) (*rsa.PublicKey, error)
 	PatchStream(ctx context.Context, streamUUID string, cfg PatchStreamConfig) (
@@ -63,6 +88,15 @@ type Server interface {
 	StorageBackup(ctx context.Context, storageName ServerStorage, w io.Writer) error
 	PatchParticipant(ctx context.Context,
 		streamUUID, participantUUID string, cfg PatchParticipantConfig) (*Participant, error)
+	StreamLogs(ctx context.Context, streamUUID string, follow bool) (io.ReadCloser, error)
+	NewStreamTemplate(ctx context.Context, tpl StreamTemplate) error
+	StreamTemplates(ctx context.Context) ([]StreamTemplate, error)
+	StreamTemplate(ctx context.Context, name string) (*StreamTemplate, error)
+	UpdateStreamTemplate(ctx context.Context, tpl StreamTemplate) error
+	DeleteStreamTemplate(ctx context.Context, name string) error
+	QuotaUsage(ctx context.Context) (*QuotaUsage, error)
+	QueueStream(ctx context.Context, cfg StreamConfig) (*StreamQueueTicket, error)
+	WatchStreamQueueTicket(ctx context.Context, ticket string) (<-chan StreamQueueTicket, error)
 }
 
 // AvatarRestrictions represents avatar restrictions model.
@@ -76,6 +110,7 @@ type ServerInfo struct {
 	Description string
 	Version     string
 	Meta        map[string]interface{}
+	LaunchModes []StreamLaunchMode
 }
 
 // StreamConfig represents stream configuration model.
@@ -86,6 +121,20 @@ type StreamConfig struct {
 	Join        StreamJoinPolicyConfig
 	Launch      StreamLaunchConfig
 	Host        StreamHostConfig
+	MaxDuration time.Duration
+	IdleTimeout time.Duration
+	StartAt     time.Time
+	Template    string
+}
+
+// StreamTemplate represents stream template model.
+type StreamTemplate struct {
+	Name        string
+	Description string
+	Join        StreamJoinPolicyConfig
+	Launch      StreamLaunchConfig
+	MaxDuration time.Duration
+	IdleTimeout time.Duration
 }
 
 // StreamJoinPolicyConfig represents stream join policy configuration model.
@@ -95,10 +144,34 @@ type StreamJoinPolicyConfig struct {
 }
 
 // StreamLaunchConfig represents stream launch configuration model.
+//
+// Env and Args are passed to the stream instance in addition to the default ones.
+// Image is a docker image of the stream instance, the default one is used if it's empty.
 type StreamLaunchConfig struct {
 	PreferredPort int
 	PreferredIP   string
 	Mode          StreamLaunchMode
+	Restart       StreamRestartConfig
+	Limits        StreamResourceLimits
+	Env           map[string]string
+	Args          []string
+	Image         string
+}
+
+// StreamResourceLimits represents stream resource limits model.
+//
+// CPU is a number of CPUs, Memory is a number of bytes and PIDs is a max number
+// of processes. Zero value means no limit.
+type StreamResourceLimits struct {
+	CPU    float64
+	Memory int64
+	PIDs   int64
+}
+
+// StreamRestartConfig represents stream restart configuration model.
+type StreamRestartConfig struct {
+	Policy      StreamRestartPolicy
+	MaxAttempts int
 }
 
 // StreamHostConfig represents stream host configuration model.
@@ -121,6 +194,7 @@ type StreamOwnerInfo struct {
 	LaunchMode  StreamLaunchMode
 	Host        HostInfo
 	Auth        *AuthInfo
+	ScheduledAt *time.Time
 }
 
 // HostInfo represents host of the stream info.
@@ -132,9 +206,14 @@ type HostInfo struct {
 }
 
 // AuthInfo represents authentication info model.
+//
+// ExpiresAt is the expiration time of the access token. RefreshToken and ExpiresAt are
+// only set for the stream tokens.
 type AuthInfo struct {
-	AccessToken string
-	Type        string
+	AccessToken  string
+	RefreshToken string
+	ExpiresAt    time.Time
+	Type         string
 }
 
 // StreamPublicInfo represents stream public info model.
@@ -145,6 +224,9 @@ type StreamPublicInfo struct {
 	JoinPolicy  JoinPolicy
 	StartedAt   time.Time
 	FinishedAt  *time.Time
+	Health      StreamHealth
+	Status      StreamStatus
+	ScheduledAt *time.Time
 }
 
 // Participant represents participant model.
@@ -154,15 +236,27 @@ type Participant struct {
 	AvatarID string
 	IP       string
 	Status   ParticipantStatus
+	Role     ParticipantRole
 }
 
 // ParticipantStatus represents participant status type.
 type ParticipantStatus string
 
+// ParticipantRole represents participant role type.
+type ParticipantRole string
+
+// JoinStatus represents join request status type.
+type JoinStatus string
+
 // JoinParticipantDecision represents join participant decision model.
+//
+// Status is expired if the host hasn't decided on the join request in time.
 type JoinParticipantDecision struct {
-	JoinAllowed bool
-	AccessToken string
+	JoinAllowed  bool
+	Status       JoinStatus
+	AccessToken  string
+	RefreshToken string
+	ExpiresAt    time.Time
 }
 
 // PatchStreamConfig represents patch stream configuration model.
@@ -213,6 +307,49 @@ type StreamInfo struct {
 	Status      StreamStatus
 	Join        StreamJoinPolicyConfig
 	Host        HostInfo
+	Restarts    int
+	Health      StreamHealth
+	Limits      StreamResourceLimits
+	ScheduledAt *time.Time
+	Worker      string
+	Error       string
+}
+
+// StreamQuota represents stream quotas model.
+//
+// Subject quotas are applied to the streams created with the token of the subject,
+// stream time is counted within the period. Zero value means no limit.
+type StreamQuota struct {
+	MaxStreams           int
+	MaxSubjectStreams    int
+	MaxSubjectStreamTime time.Duration
+	Period               time.Duration
+}
+
+// QuotaUsage represents stream quotas usage model.
+type QuotaUsage struct {
+	Limits   StreamQuota
+	Running  int
+	Subjects []SubjectQuotaUsage
+}
+
+// SubjectQuotaUsage represents stream quotas usage of the subject model.
+type SubjectQuotaUsage struct {
+	Subject    string
+	Running    int
+	StreamTime time.Duration
+}
+
+// StreamQueueTicket represents queued stream model.
+//
+// Position is a 1-based position in the queue, it's zero once the stream is created
+// or failed to be created.
+type StreamQueueTicket struct {
+	Ticket   string
+	Subject  string
+	Position int
+	Stream   *StreamOwnerInfo
+	Error    string
 }
 
 // ServerStorage represents server storage type.
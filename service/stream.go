@@ -1,6 +1,11 @@
 package service
 
-import "context"
+import (
+	"context"
+	"errors"
+	"io"
+	"regexp"
+)
 
 // Stream join policy.
 const (
@@ -13,19 +18,63 @@ const (
 const (
 	StreamLaunchModeStandaloneApp   StreamLaunchMode = "standalone_app"
 	StreamLaunchModeDockerContainer StreamLaunchMode = "docker_container"
+	StreamLaunchModeInProcess       StreamLaunchMode = "in_process"
+	StreamLaunchModeRemoteWorker    StreamLaunchMode = "remote_worker"
 )
 
+// LaunchModeNameRegexp matches valid name of the stream launch mode.
+var LaunchModeNameRegexp = regexp.MustCompile("^[a-z][a-z0-9_]*$")
+
 // Stream status.
 const (
-	StreamStatusRunning  StreamStatus = "running"
-	StreamStatusFinished StreamStatus = "finished"
+	StreamStatusScheduled StreamStatus = "scheduled"
+	StreamStatusRunning   StreamStatus = "running"
+	StreamStatusPaused    StreamStatus = "paused"
+	StreamStatusFinished  StreamStatus = "finished"
+)
+
+// Stream restart policy.
+const (
+	StreamRestartPolicyNever     StreamRestartPolicy = "never"
+	StreamRestartPolicyOnFailure StreamRestartPolicy = "on-failure"
+)
+
+// Stream health.
+const (
+	StreamHealthHealthy     StreamHealth = "healthy"
+	StreamHealthDegraded    StreamHealth = "degraded"
+	StreamHealthUnreachable StreamHealth = "unreachable"
 )
 
+// ErrStreamPaused is returned when the paused stream can't serve the request.
+var ErrStreamPaused = errors.New("stream is paused")
+
+// ErrInvalidLaunchMode is returned when the launch mode of the stream isn't registered.
+var ErrInvalidLaunchMode = errors.New("invalid launch mode")
+
+// ErrForbiddenStreamEnv is returned when the stream environment variable can't be set
+// for the stream launch mode.
+var ErrForbiddenStreamEnv = errors.New("forbidden stream environment variable")
+
+// ErrForbiddenStreamArg is returned when the stream argument overrides the flag set
+// by the server.
+var ErrForbiddenStreamArg = errors.New("forbidden stream argument")
+
+// ErrInsufficientRole is returned when the participant role doesn't allow to act on
+// another participant.
+var ErrInsufficientRole = errors.New("insufficient participant role")
+
+// ErrStreamQuotaExceeded is returned when the new stream doesn't fit the stream quotas.
+var ErrStreamQuotaExceeded = errors.New("stream quota exceeded")
+
 // Stream represents stream API.
 type Stream interface {
 	Start(ctx context.Context) (*StartStreamInfo, error)
 	Stop(ctx context.Context) error
+	Pause(ctx context.Context) error
+	Resume(ctx context.Context) error
 	InterruptNotification() <-chan error
+	Logs(ctx context.Context) (io.ReadCloser, error)
 }
 
 // StartStreamInfo represents start stream info model.
@@ -42,3 +91,9 @@ type StreamLaunchMode string
 
 // StreamStatus represents stream status.
 type StreamStatus string
+
+// StreamRestartPolicy represents policy of restarting interrupted stream.
+type StreamRestartPolicy string
+
+// StreamHealth represents stream health.
+type StreamHealth string
@@ -1,9 +1,42 @@
 package service
 
+import "time"
+
+// Stream event type.
+const (
+	StreamEventFinishWarning StreamEventType = "finish_warning"
+)
+
+// Stream finish reason.
+const (
+	StreamFinishReasonMaxDuration StreamFinishReason = "max_duration"
+	StreamFinishReasonIdle        StreamFinishReason = "idle"
+)
+
 // StreamHandler represents stream handler API.
 type StreamHandler interface {
 	NewParticipant(p StreamParticipant) error
 	ChangeParticipantInfo(p StreamParticipant) error
+	RemoveParticipant(p StreamParticipant) error
+	SendEvent(e StreamEvent) error
+}
+
+// StreamEventType represents stream event type.
+type StreamEventType string
+
+// StreamFinishReason represents reason of finishing the stream by the server.
+type StreamFinishReason string
+
+// StreamEvent represents stream event model.
+type StreamEvent struct {
+	Type    StreamEventType `json:"type"`
+	Payload interface{}     `json:"payload,omitempty"`
+}
+
+// StreamFinishWarning represents payload of the stream finish warning event.
+type StreamFinishWarning struct {
+	Reason   StreamFinishReason `json:"reason"`
+	FinishAt time.Time          `json:"finishAt"`
 }
 
 // StreamParticipant represents stream participant model.
@@ -12,5 +45,6 @@ type StreamParticipant struct {
 	Name     string            `json:"name"`
 	AvatarID string            `json:"avatarId,omitempty"`
 	Status   ParticipantStatus `json:"status"`
+	Role     ParticipantRole   `json:"role"`
 	Host     bool              `json:"isHost,omitempty"`
 }
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrWorkerFull is returned when the worker can't run one more stream.
+var ErrWorkerFull = errors.New("worker is at full capacity")
+
+// Worker represents stream worker API.
+type Worker interface {
+	Info() WorkerInfo
+	StartStream(ctx context.Context, streamUUID string, cfg StreamLaunchConfig) (
+		*StartStreamInfo, error)
+	WorkerStream(ctx context.Context, streamUUID string) (*StartStreamInfo, error)
+	StopStream(ctx context.Context, streamUUID string) error
+	PauseStream(ctx context.Context, streamUUID string) error
+	ResumeStream(ctx context.Context, streamUUID string) error
+	StreamLogs(ctx context.Context, streamUUID string) (io.ReadCloser, error)
+	WaitStream(ctx context.Context, streamUUID string) (*WorkerStreamExit, error)
+}
+
+// WorkerInfo represents worker info model.
+type WorkerInfo struct {
+	Name       string
+	Version    string
+	LaunchMode StreamLaunchMode
+	Capacity   int
+	Streams    int
+}
+
+// WorkerStreamExit represents exit info model of the stream run by the worker.
+type WorkerStreamExit struct {
+	Error string
+}
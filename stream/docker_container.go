@@ -3,14 +3,18 @@ package stream
 import (
 	"context"
 	"fmt"
+	"io"
+	"os"
 	"strconv"
+	"sync"
+	"time"
 
 	"github.com/code-cord/cc.core.server/service"
-	"github.com/code-cord/cc.core.server/util"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/strslice"
 	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/docker/go-connections/nat"
 	"github.com/sirupsen/logrus"
 )
@@ -26,8 +30,18 @@ type DockerContainerStream struct {
 	dockerImage     string
 	containerID     string
 	preferedPort    int
+	port            int
+	ports           PortReserver
+	env             map[string]string
+	args            []string
 	preferedIP      string
+	stopTimeout     time.Duration
+	limits          service.StreamResourceLimits
+	logsSince       time.Time
 	interruptChan   chan error
+	mu              sync.Mutex
+	stopped         bool
+	paused          bool
 }
 
 // DockerContainerStreamConfig represents docker container stream configuration model.
@@ -37,6 +51,11 @@ type DockerContainerStreamConfig struct {
 	DockerImage     string
 	PreferedPort    int
 	PreferedIP      string
+	StopTimeout     time.Duration
+	Limits          service.StreamResourceLimits
+	Ports           PortReserver
+	Env             map[string]string
+	Args            []string
 }
 
 // NewDockerContainerStream returns new stream as docker container instance.
@@ -47,10 +66,76 @@ func NewDockerContainerStream(cfg DockerContainerStreamConfig) *DockerContainerS
 		dockerImage:     cfg.DockerImage,
 		preferedPort:    cfg.PreferedPort,
 		preferedIP:      cfg.PreferedIP,
+		stopTimeout:     cfg.StopTimeout,
+		limits:          cfg.Limits,
+		ports:           cfg.Ports,
+		env:             cfg.Env,
+		args:            cfg.Args,
 		interruptChan:   make(chan error),
 	}
 }
 
+// FindDockerContainerStream returns stream of the already running docker container.
+//
+// If the container of the stream isn't running it returns os.ErrNotExist.
+func FindDockerContainerStream(ctx context.Context, cfg DockerContainerStreamConfig) (
+	*DockerContainerStream, error) {
+	cli, err := client.NewClientWithOpts()
+	if err != nil {
+		return nil, fmt.Errorf("could not init docker cli client: %v", err)
+	}
+
+	containerJSON, err := cli.ContainerInspect(
+		ctx, dockerContainerName(cfg.ContainerPrefix, cfg.StreamUUID))
+	if err != nil {
+		if client.IsErrNotFound(err) {
+			return nil, os.ErrNotExist
+		}
+
+		return nil, fmt.Errorf("could not inspect docker container: %v", err)
+	}
+
+	if containerJSON.State == nil || !containerJSON.State.Running {
+		return nil, os.ErrNotExist
+	}
+
+	s := NewDockerContainerStream(cfg)
+	s.containerID = containerJSON.ID
+
+	return s, nil
+}
+
+// NewDockerContainerStreamFromID returns stream of the already running docker container
+// with the provided ID.
+//
+// Unlike FindDockerContainerStream it starts listening to the container state, so
+// the returned stream reports its interruption as if it was started by Start.
+func NewDockerContainerStreamFromID(
+	ctx context.Context, cfg DockerContainerStreamConfig, containerID string) (
+	*DockerContainerStream, error) {
+	cli, err := client.NewClientWithOpts()
+	if err != nil {
+		return nil, fmt.Errorf("could not init docker cli client: %v", err)
+	}
+
+	containerJSON, err := cli.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return nil, fmt.Errorf("could not inspect docker container: %v", err)
+	}
+
+	if containerJSON.State == nil || !containerJSON.State.Running {
+		return nil, fmt.Errorf("docker container %s is not running", containerID)
+	}
+
+	s := NewDockerContainerStream(cfg)
+	s.containerID = containerJSON.ID
+	s.logsSince = time.Now()
+
+	go s.waitContainer(cli)
+
+	return s, nil
+}
+
 // Start starts docker container stream.
 func (s *DockerContainerStream) Start(ctx context.Context) (*service.StartStreamInfo, error) {
 	cli, err := client.NewClientWithOpts()
@@ -62,24 +147,30 @@ func (s *DockerContainerStream) Start(ctx context.Context) (*service.StartStream
 		s.preferedIP = defaultDockerContainerHostIP
 	}
 
-	if s.preferedPort == 0 {
-		port, err := util.FreePort(s.preferedIP)
+	if s.port == 0 && s.preferedPort != 0 {
+		if err := reservePreferredPort(s.ports, s.preferedIP, s.preferedPort); err != nil {
+			return nil, fmt.Errorf("could not reserve preferred port to run container: %v", err)
+		}
+		s.port = s.preferedPort
+	}
+	if s.port == 0 {
+		port, err := reservePort(s.ports, s.preferedIP)
 		if err != nil {
 			return nil, fmt.Errorf("could not find free port to run container: %v", err)
 		}
-		s.preferedPort = port
+		s.port = port
 	}
 
-	tcpAddress := fmt.Sprintf("%s:%d", s.preferedIP, s.preferedPort)
-	portStr := strconv.Itoa(s.preferedPort)
+	tcpAddress := fmt.Sprintf("%s:%d", s.preferedIP, s.port)
+	portStr := strconv.Itoa(s.port)
 	containerCfg := container.Config{
 		Image:        s.dockerImage,
 		ExposedPorts: nat.PortSet{nat.Port(portStr): struct{}{}},
-		Cmd: strslice.StrSlice{
-			"/start", "-addr", tcpAddress,
-		},
+		Cmd:          append(strslice.StrSlice{"/start", "-addr", tcpAddress}, s.args...),
+		Env:          envList(s.env),
 	}
 	containerHostCfg := container.HostConfig{
+		Resources: dockerContainerResources(s.limits),
 		PortBindings: map[nat.Port][]nat.PortBinding{
 			nat.Port(portStr): {
 				{
@@ -89,7 +180,17 @@ func (s *DockerContainerStream) Start(ctx context.Context) (*service.StartStream
 			},
 		},
 	}
-	containerName := fmt.Sprintf("%s-%s", s.containerPrefix, s.streamUUID)
+	containerName := dockerContainerName(s.containerPrefix, s.streamUUID)
+
+	// remove container of the previous run.
+	if s.containerID != "" {
+		err := cli.ContainerRemove(ctx, s.containerID, types.ContainerRemoveOptions{
+			Force: true,
+		})
+		if err != nil && !client.IsErrNotFound(err) {
+			return nil, fmt.Errorf("could not remove previous docker container: %v", err)
+		}
+	}
 
 	containerBody, err := cli.ContainerCreate(
 		ctx, &containerCfg, &containerHostCfg, nil, nil, containerName)
@@ -102,34 +203,220 @@ func (s *DockerContainerStream) Start(ctx context.Context) (*service.StartStream
 		logrus.Warn(containerBody.Warnings[i])
 	}
 
+	s.logsSince = time.Now()
 	if err := cli.ContainerStart(ctx, s.containerID, types.ContainerStartOptions{}); err != nil {
 		return nil, fmt.Errorf("could not start docker container: %v", err)
 	}
+	s.setStopped(false)
+	s.setPaused(false)
 
-	go func() {
-		okBodyChan, _ := cli.ContainerWait(
-			context.Background(), s.containerID, container.WaitConditionNotRunning)
-		waitOk := <-okBodyChan
-		s.interruptChan <- fmt.Errorf("status code %d: %v", waitOk.StatusCode, waitOk.Error)
-	}()
+	go s.waitContainer(cli)
 
 	return &service.StartStreamInfo{
 		IP:   s.preferedIP,
-		Port: s.preferedPort,
+		Port: s.port,
 	}, nil
 }
 
 // Stop stops running stream.
+//
+// The container is given the stop timeout to exit gracefully before it's killed.
 func (s *DockerContainerStream) Stop(ctx context.Context) error {
 	cli, err := client.NewClientWithOpts()
 	if err != nil {
 		return fmt.Errorf("could not init docker cli client: %v", err)
 	}
+	s.setStopped(true)
 
-	return cli.ContainerStop(ctx, s.containerID, nil)
+	// paused container has to be unpaused to handle the stop signal gracefully.
+	if s.isPaused() {
+		if err := cli.ContainerUnpause(ctx, s.containerID); err != nil {
+			logrus.Warnf("could not unpause docker container %s: %v", s.containerID, err)
+		}
+		s.setPaused(false)
+	}
+
+	stopTimeout := s.stopTimeout
+	if stopTimeout <= 0 {
+		stopTimeout = defaultStopTimeout
+	}
+
+	if err := cli.ContainerStop(ctx, s.containerID, &stopTimeout); err != nil {
+		return err
+	}
+	s.ResetPort()
+
+	return nil
 }
 
-// InterruptNotification returns an error when the stream has been interrupted.
+// ResetPort releases the port reserved for the stream, so the next start binds the
+// container to another one.
+//
+// It reports whether the port can be changed, which isn't the case for the preferred one.
+func (s *DockerContainerStream) ResetPort() bool {
+	if s.preferedPort != 0 {
+		return false
+	}
+
+	releasePort(s.ports, s.preferedIP, s.port)
+	s.port = 0
+
+	return true
+}
+
+// Pause suspends all processes of the stream container.
+func (s *DockerContainerStream) Pause(ctx context.Context) error {
+	cli, err := client.NewClientWithOpts()
+	if err != nil {
+		return fmt.Errorf("could not init docker cli client: %v", err)
+	}
+
+	if err := cli.ContainerPause(ctx, s.containerID); err != nil {
+		return fmt.Errorf("could not pause docker container: %v", err)
+	}
+	s.setPaused(true)
+
+	return nil
+}
+
+// Resume unpauses all processes of the stream container.
+func (s *DockerContainerStream) Resume(ctx context.Context) error {
+	cli, err := client.NewClientWithOpts()
+	if err != nil {
+		return fmt.Errorf("could not init docker cli client: %v", err)
+	}
+
+	if err := cli.ContainerUnpause(ctx, s.containerID); err != nil {
+		return fmt.Errorf("could not unpause docker container: %v", err)
+	}
+	s.setPaused(false)
+
+	return nil
+}
+
+// InterruptNotification notifies when the stream container stops running without being stopped.
+//
+// It returns nil if the container has exited successfully.
 func (s *DockerContainerStream) InterruptNotification() <-chan error {
 	return s.interruptChan
 }
+
+// Logs returns reader of the stream container stdout and stderr output.
+//
+// Only the output produced since the container has been started (or found) by
+// the current stream instance is returned. The reader follows the output until
+// the container stops running.
+func (s *DockerContainerStream) Logs(ctx context.Context) (io.ReadCloser, error) {
+	cli, err := client.NewClientWithOpts()
+	if err != nil {
+		return nil, fmt.Errorf("could not init docker cli client: %v", err)
+	}
+
+	containerLogs, err := cli.ContainerLogs(ctx, s.containerID, types.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Follow:     true,
+		Since:      strconv.FormatInt(s.logsSince.Unix(), 10),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch docker container logs: %v", err)
+	}
+
+	// container output is multiplexed, so it has to be demultiplexed first.
+	logReader, logWriter := io.Pipe()
+	go func() {
+		_, err := stdcopy.StdCopy(logWriter, logWriter, containerLogs)
+		logWriter.CloseWithError(err)
+	}()
+
+	return &dockerLogReader{
+		PipeReader:    logReader,
+		containerLogs: containerLogs,
+	}, nil
+}
+
+// ContainerID returns ID of the stream docker container.
+func (s *DockerContainerStream) ContainerID() string {
+	return s.containerID
+}
+
+func (s *DockerContainerStream) waitContainer(cli *client.Client) {
+	okBodyChan, errChan := cli.ContainerWait(
+		context.Background(), s.containerID, container.WaitConditionNotRunning)
+
+	var waitErr error
+	select {
+	case waitOk := <-okBodyChan:
+		if waitOk.StatusCode != 0 || waitOk.Error != nil {
+			waitErr = fmt.Errorf("status code %d: %v", waitOk.StatusCode, waitOk.Error)
+		}
+	case err := <-errChan:
+		waitErr = fmt.Errorf("could not wait for docker container: %v", err)
+	}
+
+	if !s.isStopped() {
+		s.interruptChan <- waitErr
+	}
+}
+
+func (s *DockerContainerStream) setStopped(stopped bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.stopped = stopped
+}
+
+func (s *DockerContainerStream) isStopped() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.stopped
+}
+
+func (s *DockerContainerStream) setPaused(paused bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.paused = paused
+}
+
+func (s *DockerContainerStream) isPaused() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.paused
+}
+
+func dockerContainerResources(limits service.StreamResourceLimits) container.Resources {
+	var resources container.Resources
+	if limits.CPU > 0 {
+		resources.NanoCPUs = int64(limits.CPU * 1e9)
+	}
+
+	if limits.Memory > 0 {
+		resources.Memory = limits.Memory
+	}
+
+	if limits.PIDs > 0 {
+		pidsLimit := limits.PIDs
+		resources.PidsLimit = &pidsLimit
+	}
+
+	return resources
+}
+
+func dockerContainerName(containerPrefix, streamUUID string) string {
+	return fmt.Sprintf("%s-%s", containerPrefix, streamUUID)
+}
+
+type dockerLogReader struct {
+	*io.PipeReader
+	containerLogs io.ReadCloser
+}
+
+// Close closes both demultiplexed and original container log readers.
+func (r *dockerLogReader) Close() error {
+	r.PipeReader.Close()
+
+	return r.containerLogs.Close()
+}
@@ -0,0 +1,46 @@
+package stream
+
+import (
+	"fmt"
+	"os"
+	"sort"
+)
+
+// baseEnvNames are environment variables of the current process passed to the stream
+// processes, the rest of the environment isn't leaked to them.
+var baseEnvNames = []string{
+	"PATH",
+	"HOME",
+	"USER",
+	"LANG",
+	"LC_ALL",
+	"TZ",
+	"TMPDIR",
+	"TMP",
+	"TEMP",
+	"SYSTEMROOT",
+}
+
+// envList returns environment variables in "key=value" format sorted by the key.
+func envList(env map[string]string) []string {
+	list := make([]string, 0, len(env))
+	for name, value := range env {
+		list = append(list, fmt.Sprintf("%s=%s", name, value))
+	}
+	sort.Strings(list)
+
+	return list
+}
+
+// processEnv returns environment of the stream process made of the base environment
+// of the current process and the stream environment variables.
+func processEnv(env map[string]string) []string {
+	list := make([]string, 0, len(baseEnvNames)+len(env))
+	for _, name := range baseEnvNames {
+		if value, ok := os.LookupEnv(name); ok {
+			list = append(list, fmt.Sprintf("%s=%s", name, value))
+		}
+	}
+
+	return append(list, envList(env)...)
+}
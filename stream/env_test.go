@@ -0,0 +1,30 @@
+package stream
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestProcessEnv(t *testing.T) {
+	os.Setenv("CODE_CORD_TEST_SECRET", "secret")
+	defer os.Unsetenv("CODE_CORD_TEST_SECRET")
+
+	env := processEnv(map[string]string{
+		"STREAM_THEME": "dark",
+	})
+
+	var hasStreamEnv bool
+	for _, item := range env {
+		if strings.HasPrefix(item, "CODE_CORD_TEST_SECRET=") {
+			t.Errorf("server environment is leaked to the stream: %s", item)
+		}
+		if item == "STREAM_THEME=dark" {
+			hasStreamEnv = true
+		}
+	}
+
+	if !hasStreamEnv {
+		t.Errorf("stream environment is not set: %v", env)
+	}
+}
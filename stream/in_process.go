@@ -0,0 +1,204 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/code-cord/cc.core.server/service"
+)
+
+const (
+	defaultInProcessStreamIP = "127.0.0.1"
+)
+
+// InProcessStream represents stream as http.Handler served by the current process
+// implementation model.
+type InProcessStream struct {
+	preferedIP    string
+	preferedPort  int
+	handler       http.Handler
+	stopTimeout   time.Duration
+	httpServer    *http.Server
+	logReader     io.ReadCloser
+	interruptChan chan error
+	mu            sync.Mutex
+	stopped       bool
+	resumed       chan struct{}
+}
+
+// InProcessStreamConfig represents in-process stream configuration model.
+type InProcessStreamConfig struct {
+	PreferedIP   string
+	PreferedPort int
+	Handler      http.Handler
+	StopTimeout  time.Duration
+}
+
+// NewInProcessStream returns new in-process stream instance.
+func NewInProcessStream(cfg InProcessStreamConfig) *InProcessStream {
+	return &InProcessStream{
+		preferedIP:    cfg.PreferedIP,
+		preferedPort:  cfg.PreferedPort,
+		handler:       cfg.Handler,
+		stopTimeout:   cfg.StopTimeout,
+		interruptChan: make(chan error),
+	}
+}
+
+// Start starts serving the stream handler on a local listener.
+//
+// If the preferred port isn't set, the listener is bound to any free port.
+func (s *InProcessStream) Start(ctx context.Context) (*service.StartStreamInfo, error) {
+	if s.handler == nil {
+		return nil, errors.New("stream handler is not set")
+	}
+
+	if s.preferedIP == "" {
+		s.preferedIP = defaultInProcessStreamIP
+	}
+
+	listener, err := net.Listen("tcp", net.JoinHostPort(s.preferedIP, strconv.Itoa(s.preferedPort)))
+	if err != nil {
+		return nil, fmt.Errorf("could not listen stream address: %v", err)
+	}
+	s.preferedPort = listener.Addr().(*net.TCPAddr).Port
+
+	logReader, logWriter := io.Pipe()
+	s.httpServer = &http.Server{
+		Handler:  http.HandlerFunc(s.serveHTTP),
+		ErrorLog: log.New(logWriter, "", log.LstdFlags),
+	}
+	s.logReader = logReader
+	s.setStopped(false)
+	s.release()
+
+	go func(httpServer *http.Server, logWriter *io.PipeWriter) {
+		err := httpServer.Serve(listener)
+		logWriter.Close()
+		if err == http.ErrServerClosed {
+			err = nil
+		}
+
+		if !s.isStopped() {
+			s.interruptChan <- err
+		}
+	}(s.httpServer, logWriter)
+
+	return &service.StartStreamInfo{
+		IP:   s.preferedIP,
+		Port: s.preferedPort,
+	}, nil
+}
+
+// Stop gracefully shuts down the stream server.
+//
+// Active requests are given the stop timeout to complete before the server is closed.
+func (s *InProcessStream) Stop(ctx context.Context) error {
+	s.setStopped(true)
+	s.release()
+
+	stopTimeout := s.stopTimeout
+	if stopTimeout <= 0 {
+		stopTimeout = defaultStopTimeout
+	}
+	shutdownCtx, cancel := context.WithTimeout(ctx, stopTimeout)
+	defer cancel()
+
+	if err := s.httpServer.Shutdown(shutdownCtx); err != nil {
+		if closeErr := s.httpServer.Close(); closeErr != nil {
+			return fmt.Errorf("could not close stream server: %v", closeErr)
+		}
+	}
+
+	return nil
+}
+
+// Pause holds all incoming requests of the stream until it's resumed.
+func (s *InProcessStream) Pause(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	select {
+	case <-s.resumed:
+		s.resumed = make(chan struct{})
+	default:
+	}
+
+	return nil
+}
+
+// Resume lets the held requests of the stream to be served.
+func (s *InProcessStream) Resume(ctx context.Context) error {
+	s.release()
+
+	return nil
+}
+
+// InterruptNotification notifies when the stream server stops serving without being stopped.
+func (s *InProcessStream) InterruptNotification() <-chan error {
+	return s.interruptChan
+}
+
+// Logs returns reader of the stream server error log.
+//
+// The log of the current run can be read only once and must be read continuously,
+// otherwise the stream server is blocked on writing it.
+func (s *InProcessStream) Logs(ctx context.Context) (io.ReadCloser, error) {
+	if s.logReader == nil {
+		return nil, errors.New("stream server log is not available")
+	}
+
+	return s.logReader, nil
+}
+
+func (s *InProcessStream) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	resumed := s.resumed
+	s.mu.Unlock()
+
+	select {
+	case <-resumed:
+	case <-r.Context().Done():
+		return
+	}
+
+	s.handler.ServeHTTP(w, r)
+}
+
+// release releases the held requests of the paused stream.
+func (s *InProcessStream) release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.resumed == nil {
+		s.resumed = make(chan struct{})
+	}
+
+	select {
+	case <-s.resumed:
+	default:
+		close(s.resumed)
+	}
+}
+
+func (s *InProcessStream) setStopped(stopped bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.stopped = stopped
+}
+
+func (s *InProcessStream) isStopped() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.stopped
+}
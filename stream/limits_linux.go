@@ -0,0 +1,140 @@
+//go:build linux
+
+package stream
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/code-cord/cc.core.server/service"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	cgroupRoot        = "/sys/fs/cgroup"
+	cgroupCPUPeriod   = 100000
+	cgroupControllers = "+cpu +memory +pids"
+)
+
+// prepareResourceLimits creates cgroup v2 limiting resources of the stream process
+// and makes the cmd start the process right in it, so the limits apply from the
+// very first instruction of the process. If cgroup v2 can't be used, the process
+// is started without limits.
+//
+// It returns path of the created cgroup, if any, along with the func releasing the
+// cgroup descriptor which has to be called once the cmd is started.
+func prepareResourceLimits(cmd *exec.Cmd, cgroupParent string, limits service.StreamResourceLimits) (
+	string, func(), error) {
+	if isEmptyResourceLimits(limits) {
+		return "", func() {}, nil
+	}
+
+	cgroupPath, err := createCgroup(cgroupParent, limits)
+	if err != nil {
+		logrus.Warnf("resource limits of stream %s can't be applied: %v", cmd.Path, err)
+		return "", func() {}, nil
+	}
+
+	cgroup, err := os.Open(cgroupPath)
+	if err != nil {
+		os.Remove(cgroupPath)
+		return "", nil, fmt.Errorf("could not open cgroup: %v", err)
+	}
+
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.UseCgroupFD = true
+	cmd.SysProcAttr.CgroupFD = int(cgroup.Fd())
+
+	return cgroupPath, func() { cgroup.Close() }, nil
+}
+
+// removeCgroup removes cgroup of the stream process once the process has exited.
+func removeCgroup(cgroupPath string) error {
+	if cgroupPath == "" {
+		return nil
+	}
+
+	if err := os.Remove(cgroupPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("could not remove %s cgroup: %v", cgroupPath, err)
+	}
+
+	return nil
+}
+
+func createCgroup(cgroupParent string, limits service.StreamResourceLimits) (path string, err error) {
+	if _, err := os.Stat(filepath.Join(cgroupRoot, "cgroup.controllers")); err != nil {
+		return "", fmt.Errorf("cgroup v2 is not available: %v", err)
+	}
+
+	parentPath := filepath.Join(cgroupRoot, cgroupParent)
+	if err := os.MkdirAll(parentPath, 0755); err != nil {
+		return "", fmt.Errorf("could not create parent cgroup: %v", err)
+	}
+
+	// controllers have to be enabled for every cgroup down to the stream one.
+	for dir := cgroupRoot; ; {
+		if err := writeCgroupFile(dir, "cgroup.subtree_control", cgroupControllers); err != nil {
+			return "", err
+		}
+		if dir == parentPath {
+			break
+		}
+
+		rel, err := filepath.Rel(dir, parentPath)
+		if err != nil {
+			return "", fmt.Errorf("could not resolve parent cgroup path: %v", err)
+		}
+		dir = filepath.Join(dir, strings.Split(rel, string(filepath.Separator))[0])
+	}
+
+	path, err = os.MkdirTemp(parentPath, "stream-")
+	if err != nil {
+		return "", fmt.Errorf("could not create cgroup: %v", err)
+	}
+	defer func() {
+		if err != nil {
+			os.Remove(path)
+		}
+	}()
+
+	if limits.CPU > 0 {
+		quota := int64(limits.CPU * cgroupCPUPeriod)
+		cpuMax := fmt.Sprintf("%d %d", quota, cgroupCPUPeriod)
+		if err := writeCgroupFile(path, "cpu.max", cpuMax); err != nil {
+			return "", err
+		}
+	}
+
+	if limits.Memory > 0 {
+		memoryMax := strconv.FormatInt(limits.Memory, 10)
+		if err := writeCgroupFile(path, "memory.max", memoryMax); err != nil {
+			return "", err
+		}
+	}
+
+	if limits.PIDs > 0 {
+		pidsMax := strconv.FormatInt(limits.PIDs, 10)
+		if err := writeCgroupFile(path, "pids.max", pidsMax); err != nil {
+			return "", err
+		}
+	}
+
+	return path, nil
+}
+
+func writeCgroupFile(cgroupPath, name, value string) error {
+	filePath := filepath.Join(cgroupPath, name)
+	if err := ioutil.WriteFile(filePath, []byte(value), 0644); err != nil {
+		return fmt.Errorf("could not write %s: %v", filePath, err)
+	}
+
+	return nil
+}
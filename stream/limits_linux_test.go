@@ -0,0 +1,58 @@
+//go:build linux
+
+package stream
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/code-cord/cc.core.server/service"
+)
+
+func TestPrepareResourceLimitsWithoutLimits(t *testing.T) {
+	cmd := exec.Command("true")
+	cgroupPath, release, err := prepareResourceLimits(cmd, "code-cord-test",
+		service.StreamResourceLimits{})
+	if err != nil {
+		t.Fatalf("could not prepare resource limits: %v", err)
+	}
+	release()
+
+	if cgroupPath != "" {
+		t.Errorf("cgroup %s is created without limits", cgroupPath)
+	}
+	if cmd.SysProcAttr != nil && cmd.SysProcAttr.UseCgroupFD {
+		t.Error("process is started in cgroup without limits")
+	}
+}
+
+func TestPrepareResourceLimitsStartsProcessInCgroup(t *testing.T) {
+	if _, err := os.Stat(filepath.Join(cgroupRoot, "cgroup.controllers")); err != nil {
+		t.Skip("cgroup v2 is not available")
+	}
+
+	cmd := exec.Command("cat", "/proc/self/cgroup")
+	cgroupPath, release, err := prepareResourceLimits(cmd, "code-cord-test",
+		service.StreamResourceLimits{PIDs: 16})
+	if err != nil {
+		t.Fatalf("could not prepare resource limits: %v", err)
+	}
+	if cgroupPath == "" {
+		t.Skip("cgroup v2 can't be used")
+	}
+	defer os.Remove(filepath.Dir(cgroupPath))
+	defer removeCgroup(cgroupPath)
+
+	out, err := cmd.Output()
+	release()
+	if err != nil {
+		t.Fatalf("could not run process: %v", err)
+	}
+
+	if !strings.Contains(string(out), filepath.Base(cgroupPath)) {
+		t.Errorf("process isn't started in %s cgroup: %s", cgroupPath, out)
+	}
+}
@@ -0,0 +1,28 @@
+//go:build !linux
+
+package stream
+
+import (
+	"os/exec"
+	"runtime"
+
+	"github.com/code-cord/cc.core.server/service"
+	"github.com/sirupsen/logrus"
+)
+
+// prepareResourceLimits limits resources of the stream process.
+//
+// Resource limits of standalone streams are supported on linux only.
+func prepareResourceLimits(cmd *exec.Cmd, cgroupParent string, limits service.StreamResourceLimits) (
+	string, func(), error) {
+	if !isEmptyResourceLimits(limits) {
+		logrus.Warnf("resource limits of stream %s are not supported on %s",
+			cmd.Path, runtime.GOOS)
+	}
+
+	return "", func() {}, nil
+}
+
+func removeCgroup(cgroupPath string) error {
+	return nil
+}
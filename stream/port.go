@@ -0,0 +1,32 @@
+package stream
+
+import "github.com/code-cord/cc.core.server/util"
+
+// PortReserver represents reserver of the ports the streams are bound to.
+type PortReserver interface {
+	Reserve(ip string) (int, error)
+	ReservePort(ip string, port int) error
+	Release(ip string, port int)
+}
+
+func reservePort(ports PortReserver, ip string) (int, error) {
+	if ports == nil {
+		return util.FreePort(ip)
+	}
+
+	return ports.Reserve(ip)
+}
+
+func reservePreferredPort(ports PortReserver, ip string, port int) error {
+	if ports == nil {
+		return nil
+	}
+
+	return ports.ReservePort(ip, port)
+}
+
+func releasePort(ports PortReserver, ip string, port int) {
+	if ports != nil && port != 0 {
+		ports.Release(ip, port)
+	}
+}
@@ -0,0 +1,252 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/code-cord/cc.core.server/cli"
+	"github.com/code-cord/cc.core.server/service"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultWorkerWaitRetryCount   = 3
+	defaultWorkerWaitRetryTimeout = time.Second
+)
+
+// RemoteWorkerStream represents stream run by the remote worker implementation model.
+type RemoteWorkerStream struct {
+	streamUUID      string
+	workerAddress   string
+	client          cli.WorkerClient
+	fallbackWorkers []cli.WorkerClientConfig
+	preferedIP      string
+	preferedPort    int
+	limits          service.StreamResourceLimits
+	env             map[string]string
+	args            []string
+	image           string
+	interruptChan   chan error
+	cancelWait      context.CancelFunc
+	mu              sync.Mutex
+	stopped         bool
+}
+
+// RemoteWorkerStreamConfig represents remote worker stream configuration model.
+type RemoteWorkerStreamConfig struct {
+	StreamUUID      string
+	Worker          cli.WorkerClientConfig
+	FallbackWorkers []cli.WorkerClientConfig
+	PreferedIP      string
+	PreferedPort    int
+	Limits          service.StreamResourceLimits
+	Env             map[string]string
+	Args            []string
+	Image           string
+}
+
+// NewRemoteWorkerStream returns new remote worker stream instance.
+func NewRemoteWorkerStream(cfg RemoteWorkerStreamConfig) *RemoteWorkerStream {
+	return &RemoteWorkerStream{
+		streamUUID:      cfg.StreamUUID,
+		workerAddress:   cfg.Worker.Address,
+		client:          cli.NewWorkerClient(cfg.Worker),
+		fallbackWorkers: cfg.FallbackWorkers,
+		preferedIP:      cfg.PreferedIP,
+		preferedPort:    cfg.PreferedPort,
+		limits:          cfg.Limits,
+		env:             cfg.Env,
+		args:            cfg.Args,
+		image:           cfg.Image,
+		interruptChan:   make(chan error),
+	}
+}
+
+// FindRemoteWorkerStream returns stream which is still run by the remote worker.
+//
+// If the worker doesn't run the stream anymore it returns os.ErrNotExist.
+func FindRemoteWorkerStream(ctx context.Context, cfg RemoteWorkerStreamConfig) (
+	*RemoteWorkerStream, error) {
+	s := NewRemoteWorkerStream(cfg)
+	if _, err := s.client.WorkerStream(ctx, s.streamUUID); err != nil {
+		return nil, err
+	}
+
+	s.waitStream()
+
+	return s, nil
+}
+
+// Start starts the stream on the remote worker.
+//
+// If the worker is at full capacity by the time the stream is started, the stream is
+// started on the next fallback worker.
+func (s *RemoteWorkerStream) Start(ctx context.Context) (*service.StartStreamInfo, error) {
+	cfg := service.StreamLaunchConfig{
+		PreferredPort: s.preferedPort,
+		PreferredIP:   s.preferedIP,
+		Limits:        s.limits,
+		Env:           s.env,
+		Args:          s.args,
+		Image:         s.image,
+	}
+
+	startInfo, err := s.client.StartStream(ctx, s.streamUUID, cfg)
+	for errors.Is(err, service.ErrWorkerFull) && len(s.fallbackWorkers) != 0 {
+		logrus.Warnf("%s worker is at full capacity, starting stream %s on %s worker",
+			s.workerAddress, s.streamUUID, s.fallbackWorkers[0].Address)
+
+		s.workerAddress = s.fallbackWorkers[0].Address
+		s.client = cli.NewWorkerClient(s.fallbackWorkers[0])
+		s.fallbackWorkers = s.fallbackWorkers[1:]
+
+		startInfo, err = s.client.StartStream(ctx, s.streamUUID, cfg)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not start stream on %s worker: %w", s.workerAddress, err)
+	}
+	s.setStopped(false)
+	s.waitStream()
+
+	return startInfo, nil
+}
+
+// Stop stops the stream on the remote worker.
+func (s *RemoteWorkerStream) Stop(ctx context.Context) error {
+	s.setStopped(true)
+
+	if err := s.client.StopStream(ctx, s.streamUUID); err != nil {
+		return fmt.Errorf("could not stop stream on %s worker: %v", s.workerAddress, err)
+	}
+
+	return nil
+}
+
+// Pause pauses the stream on the remote worker.
+func (s *RemoteWorkerStream) Pause(ctx context.Context) error {
+	if err := s.client.PauseStream(ctx, s.streamUUID); err != nil {
+		return fmt.Errorf("could not pause stream on %s worker: %v", s.workerAddress, err)
+	}
+
+	return nil
+}
+
+// Resume resumes the stream on the remote worker.
+func (s *RemoteWorkerStream) Resume(ctx context.Context) error {
+	if err := s.client.ResumeStream(ctx, s.streamUUID); err != nil {
+		return fmt.Errorf("could not resume stream on %s worker: %v", s.workerAddress, err)
+	}
+
+	return nil
+}
+
+// InterruptNotification notifies when the stream exits on the remote worker without
+// being stopped or the worker becomes unreachable.
+func (s *RemoteWorkerStream) InterruptNotification() <-chan error {
+	return s.interruptChan
+}
+
+// Logs returns reader of the stream output fetched from the remote worker.
+//
+// The reader follows the output until the stream exits.
+func (s *RemoteWorkerStream) Logs(ctx context.Context) (io.ReadCloser, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	logReader, logWriter := io.Pipe()
+	go func() {
+		err := s.client.StreamLogs(ctx, s.streamUUID, logWriter)
+		logWriter.CloseWithError(err)
+	}()
+
+	return &remoteLogReader{
+		PipeReader: logReader,
+		cancel:     cancel,
+	}, nil
+}
+
+// WorkerAddress returns address of the remote worker running the stream.
+func (s *RemoteWorkerStream) WorkerAddress() string {
+	return s.workerAddress
+}
+
+// waitStream starts waiting for the stream to exit on the remote worker.
+func (s *RemoteWorkerStream) waitStream() {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	s.mu.Lock()
+	if s.cancelWait != nil {
+		s.cancelWait()
+	}
+	s.cancelWait = cancel
+	s.mu.Unlock()
+
+	go func() {
+		defer cancel()
+
+		var waitErr error
+		for i := 0; i < defaultWorkerWaitRetryCount; i++ {
+			exit, err := s.client.WaitStream(ctx, s.streamUUID)
+			if err == nil {
+				waitErr = nil
+				if exit.Error != "" {
+					waitErr = errors.New(exit.Error)
+				}
+				break
+			}
+
+			if ctx.Err() != nil || s.isStopped() {
+				return
+			}
+			waitErr = fmt.Errorf("could not wait for stream on %s worker: %v", s.workerAddress, err)
+
+			// the worker responded, so the stream is gone.
+			if cli.IsResponseError(err) {
+				break
+			}
+			select {
+			case <-time.After(defaultWorkerWaitRetryTimeout):
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		// the wait is canceled once the stream is stopped, nobody listens to the
+		// interruption then.
+		select {
+		case s.interruptChan <- waitErr:
+		case <-ctx.Done():
+		}
+	}()
+}
+
+func (s *RemoteWorkerStream) setStopped(stopped bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.stopped = stopped
+	if stopped && s.cancelWait != nil {
+		s.cancelWait()
+		s.cancelWait = nil
+	}
+}
+
+func (s *RemoteWorkerStream) isStopped() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.stopped
+}
+
+type remoteLogReader struct {
+	*io.PipeReader
+	cancel context.CancelFunc
+}
+
+func (r *remoteLogReader) Close() error {
+	r.cancel()
+
+	return r.PipeReader.Close()
+}
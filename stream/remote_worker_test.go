@@ -0,0 +1,206 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/code-cord/cc.core.server/cli"
+	workerhandler "github.com/code-cord/cc.core.server/handler/worker"
+	"github.com/code-cord/cc.core.server/service"
+)
+
+const testWorkerToken = "worker-token"
+
+// testWorker represents worker running a single fake stream.
+type testWorker struct {
+	mu      sync.Mutex
+	full    bool
+	running bool
+	exited  chan string
+}
+
+func (w *testWorker) Info() service.WorkerInfo {
+	return service.WorkerInfo{}
+}
+
+func (w *testWorker) StartStream(ctx context.Context, streamUUID string, cfg service.StreamLaunchConfig) (
+	*service.StartStreamInfo, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.full {
+		return nil, service.ErrWorkerFull
+	}
+	w.running = true
+
+	return &service.StartStreamInfo{
+		IP:   "127.0.0.1",
+		Port: 9000,
+	}, nil
+}
+
+func (w *testWorker) WorkerStream(ctx context.Context, streamUUID string) (*service.StartStreamInfo, error) {
+	return nil, os.ErrNotExist
+}
+
+func (w *testWorker) StopStream(ctx context.Context, streamUUID string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !w.running {
+		return os.ErrNotExist
+	}
+	w.running = false
+
+	return nil
+}
+
+func (w *testWorker) PauseStream(ctx context.Context, streamUUID string) error {
+	return nil
+}
+
+func (w *testWorker) ResumeStream(ctx context.Context, streamUUID string) error {
+	return nil
+}
+
+func (w *testWorker) StreamLogs(ctx context.Context, streamUUID string) (io.ReadCloser, error) {
+	return nil, os.ErrNotExist
+}
+
+func (w *testWorker) WaitStream(ctx context.Context, streamUUID string) (*service.WorkerStreamExit, error) {
+	select {
+	case exitErr := <-w.exited:
+		return &service.WorkerStreamExit{
+			Error: exitErr,
+		}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func startTestWorker(t *testing.T, w *testWorker) cli.WorkerClientConfig {
+	t.Helper()
+
+	srv := httptest.NewServer(workerhandler.New(workerhandler.Config{
+		Worker: w,
+		Token:  testWorkerToken,
+	}))
+	t.Cleanup(srv.Close)
+
+	return cli.WorkerClientConfig{
+		Address: strings.TrimPrefix(srv.URL, "http://"),
+		Token:   testWorkerToken,
+	}
+}
+
+func newTestRemoteWorkerStream(t *testing.T, w *testWorker) *RemoteWorkerStream {
+	t.Helper()
+
+	return NewRemoteWorkerStream(RemoteWorkerStreamConfig{
+		StreamUUID: "6b9c3bc8-3e8d-4b8a-9d32-4f4d2b0a1c11",
+		Worker:     startTestWorker(t, w),
+	})
+}
+
+func TestRemoteWorkerStreamInterrupt(t *testing.T) {
+	w := testWorker{
+		exited: make(chan string),
+	}
+	s := newTestRemoteWorkerStream(t, &w)
+
+	startInfo, err := s.Start(context.Background())
+	if err != nil {
+		t.Fatalf("could not start stream: %v", err)
+	}
+	if startInfo.IP != "127.0.0.1" || startInfo.Port != 9000 {
+		t.Errorf("unexpected start info: %+v", startInfo)
+	}
+
+	w.exited <- "stream crashed"
+
+	select {
+	case err := <-s.InterruptNotification():
+		if err == nil || err.Error() != "stream crashed" {
+			t.Errorf("unexpected interruption: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("stream interruption isn't notified")
+	}
+}
+
+func TestRemoteWorkerStreamStop(t *testing.T) {
+	w := testWorker{
+		exited: make(chan string),
+	}
+	s := newTestRemoteWorkerStream(t, &w)
+
+	if _, err := s.Start(context.Background()); err != nil {
+		t.Fatalf("could not start stream: %v", err)
+	}
+
+	if err := s.Stop(context.Background()); err != nil {
+		t.Fatalf("could not stop stream: %v", err)
+	}
+
+	select {
+	case err := <-s.InterruptNotification():
+		t.Errorf("stopped stream is interrupted: %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// the worker doesn't run the stream anymore.
+	if err := s.Stop(context.Background()); err == nil {
+		t.Error("stream is stopped twice on the worker")
+	}
+}
+
+func TestRemoteWorkerStreamStartOnFallbackWorker(t *testing.T) {
+	full := testWorker{
+		full: true,
+	}
+	fallback := testWorker{
+		exited: make(chan string),
+	}
+	fallbackWorker := startTestWorker(t, &fallback)
+
+	s := NewRemoteWorkerStream(RemoteWorkerStreamConfig{
+		StreamUUID: "6b9c3bc8-3e8d-4b8a-9d32-4f4d2b0a1c11",
+		Worker:     startTestWorker(t, &full),
+		FallbackWorkers: []cli.WorkerClientConfig{
+			startTestWorker(t, &testWorker{full: true}),
+			fallbackWorker,
+		},
+	})
+
+	if _, err := s.Start(context.Background()); err != nil {
+		t.Fatalf("could not start stream: %v", err)
+	}
+	defer s.Stop(context.Background())
+
+	if s.WorkerAddress() != fallbackWorker.Address {
+		t.Errorf("stream is started on %s worker, want %s", s.WorkerAddress(), fallbackWorker.Address)
+	}
+	fallback.mu.Lock()
+	defer fallback.mu.Unlock()
+	if !fallback.running {
+		t.Error("stream isn't started on the fallback worker")
+	}
+}
+
+func TestRemoteWorkerStreamStartOnFullWorker(t *testing.T) {
+	s := newTestRemoteWorkerStream(t, &testWorker{
+		full: true,
+	})
+
+	_, err := s.Start(context.Background())
+	if !errors.Is(err, service.ErrWorkerFull) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -2,27 +2,52 @@ package stream
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
 	"os/exec"
 	"path"
+	"path/filepath"
 	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/code-cord/cc.core.server/service"
-	"github.com/code-cord/cc.core.server/util"
+	"github.com/sirupsen/logrus"
 )
 
 const (
 	defaultStreamBin       = "stream"
 	defaultStandaloneAppIP = "127.0.0.1"
+	defaultStopTimeout     = 10 * time.Second
 )
 
 // StandaloneStream represents stream as standalone running app implementation model.
 type StandaloneStream struct {
 	preferedIP    string
 	preferedPort  int
+	port          int
+	ports         PortReserver
+	env           map[string]string
+	args          []string
 	binPath       string
 	binCmd        *exec.Cmd
+	process       *os.Process
+	pidFile       string
+	limits        service.StreamResourceLimits
+	cgroupParent  string
+	cgroupPath    string
+	stopTimeout   time.Duration
+	exited        chan struct{}
+	logReader     io.ReadCloser
 	interruptChan chan error
+	mu            sync.Mutex
+	stopped       bool
+	paused        bool
 }
 
 // StandaloneStreamConfig represents standalone stream configuration model.
@@ -30,6 +55,13 @@ type StandaloneStreamConfig struct {
 	PreferedIP   string
 	PreferedPort int
 	BinPath      string
+	StopTimeout  time.Duration
+	PIDFile      string
+	Limits       service.StreamResourceLimits
+	CgroupParent string
+	Ports        PortReserver
+	Env          map[string]string
+	Args         []string
 }
 
 // NewStandaloneStream returns new standalone stream instance.
@@ -38,55 +70,308 @@ func NewStandaloneStream(cfg StandaloneStreamConfig) *StandaloneStream {
 		preferedIP:    cfg.PreferedIP,
 		preferedPort:  cfg.PreferedPort,
 		binPath:       cfg.BinPath,
+		stopTimeout:   cfg.StopTimeout,
+		pidFile:       cfg.PIDFile,
+		limits:        cfg.Limits,
+		cgroupParent:  cfg.CgroupParent,
+		ports:         cfg.Ports,
+		env:           cfg.Env,
+		args:          cfg.Args,
 		interruptChan: make(chan error),
 	}
 }
 
+// FindStandaloneStream returns stream of the process left running by the previous
+// server run.
+//
+// The process is looked up by the PID file of the stream. If there is no such file
+// or the process isn't running the stream binary anymore it returns os.ErrNotExist.
+func FindStandaloneStream(cfg StandaloneStreamConfig) (*StandaloneStream, error) {
+	data, err := ioutil.ReadFile(cfg.PIDFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, os.ErrNotExist
+		}
+
+		return nil, fmt.Errorf("could not read %s PID file: %v", cfg.PIDFile, err)
+	}
+
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, fmt.Errorf("could not parse %s PID file: %v", cfg.PIDFile, err)
+	}
+
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return nil, os.ErrNotExist
+	}
+
+	s := NewStandaloneStream(cfg)
+	if !isStreamProcess(process, resolveBinPath(s.binPath, defaultStreamBin)) {
+		return nil, os.ErrNotExist
+	}
+	s.process = process
+	s.exited = make(chan struct{})
+
+	go waitProcess(process, s.exited)
+
+	return s, nil
+}
+
 // Start starts standalone stream.
+//
+// The stream process gets only the base environment of the current process along
+// with the stream environment variables.
 func (s *StandaloneStream) Start(ctx context.Context) (*service.StartStreamInfo, error) {
 	if s.preferedIP == "" {
 		s.preferedIP = defaultStandaloneAppIP
 	}
 
-	if s.preferedPort == 0 {
-		port, err := util.FreePort(s.preferedIP)
+	if s.port == 0 && s.preferedPort != 0 {
+		if err := reservePreferredPort(s.ports, s.preferedIP, s.preferedPort); err != nil {
+			return nil, fmt.Errorf("could not reserve preferred port to run stream: %v", err)
+		}
+		s.port = s.preferedPort
+	}
+	if s.port == 0 {
+		port, err := reservePort(s.ports, s.preferedIP)
 		if err != nil {
 			return nil, fmt.Errorf("could not find free port to run stream: %v", err)
 		}
-		s.preferedPort = port
+		s.port = port
 	}
 
-	tcpAddress := fmt.Sprintf("%s:%d", s.preferedIP, s.preferedPort)
+	tcpAddress := fmt.Sprintf("%s:%d", s.preferedIP, s.port)
 	streamPath := resolveBinPath(s.binPath, defaultStreamBin)
-	s.binCmd = exec.Command(streamPath, "-addr", tcpAddress)
+	s.binCmd = exec.Command(streamPath, append([]string{"-addr", tcpAddress}, s.args...)...)
+	s.binCmd.Env = processEnv(s.env)
+	setProcessGroup(s.binCmd)
 
-	if err := s.binCmd.Start(); err != nil {
+	// remove cgroup of the previous run.
+	if err := removeCgroup(s.cgroupPath); err != nil {
+		logrus.Warn(err)
+	}
+	s.cgroupPath = ""
+
+	cgroupPath, releaseCgroup, err := prepareResourceLimits(s.binCmd, s.cgroupParent, s.limits)
+	if err != nil {
+		return nil, fmt.Errorf("could not apply stream resource limits: %v", err)
+	}
+
+	logReader, logWriter := io.Pipe()
+	s.binCmd.Stdout = logWriter
+	s.binCmd.Stderr = logWriter
+
+	err = s.binCmd.Start()
+	releaseCgroup()
+	if err != nil {
+		logWriter.Close()
+		if err := removeCgroup(cgroupPath); err != nil {
+			logrus.Warn(err)
+		}
 		return nil, err
 	}
+	s.cgroupPath = cgroupPath
+	s.process = s.binCmd.Process
+	s.logReader = logReader
+	s.setStopped(false)
+	s.setPaused(false)
+	s.exited = make(chan struct{})
 
-	go func() {
-		err := s.binCmd.Wait()
-		if err != nil {
+	go func(cmd *exec.Cmd, exited chan struct{}, logWriter *io.PipeWriter) {
+		err := cmd.Wait()
+		logWriter.Close()
+		close(exited)
+
+		if !s.isStopped() {
 			s.interruptChan <- err
 		}
-	}()
+	}(s.binCmd, s.exited, logWriter)
+
+	if err := s.writePIDFile(); err != nil {
+		s.kill()
+		return nil, err
+	}
 
 	return &service.StartStreamInfo{
 		IP:   s.preferedIP,
-		Port: s.preferedPort,
+		Port: s.port,
 	}, nil
 }
 
 // Stop stops running stream.
+//
+// The stream process is asked to terminate with SIGTERM first. If it doesn't exit
+// within the stop timeout or the ctx is done, the whole process group is killed.
 func (s *StandaloneStream) Stop(ctx context.Context) error {
-	return s.binCmd.Process.Kill()
+	s.setStopped(true)
+
+	process := s.process
+	if err := terminateProcess(process); err != nil {
+		logrus.Warnf("could not terminate stream process %d: %v", process.Pid, err)
+	}
+
+	// stopped processes have to be continued to handle the termination signal.
+	if s.isPaused() {
+		if err := resumeProcessGroup(process); err != nil {
+			logrus.Warnf("could not resume paused stream process %d: %v", process.Pid, err)
+		}
+		s.setPaused(false)
+	}
+
+	stopTimeout := s.stopTimeout
+	if stopTimeout <= 0 {
+		stopTimeout = defaultStopTimeout
+	}
+	timer := time.NewTimer(stopTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-s.exited:
+	case <-timer.C:
+		logrus.Warnf("stream process %d didn't exit in %v, killing it", process.Pid, stopTimeout)
+	case <-ctx.Done():
+		logrus.Warnf("stream process %d is killed: %v", process.Pid, ctx.Err())
+	}
+
+	// kill the rest of the process group, including the stream process if it's still running.
+	if err := killProcessGroup(process); err != nil {
+		return fmt.Errorf("could not kill stream process group: %v", err)
+	}
+
+	if s.cgroupPath != "" {
+		select {
+		case <-s.exited:
+			if err := removeCgroup(s.cgroupPath); err != nil {
+				logrus.Warn(err)
+			}
+		case <-ctx.Done():
+		}
+	}
+	s.ResetPort()
+
+	return s.removePIDFile()
 }
 
-// InterruptNotification returns an error when the stream has been interrupted.
+// ResetPort releases the port reserved for the stream, so the next start binds the
+// stream to another one.
+//
+// It reports whether the port can be changed, which isn't the case for the preferred one.
+func (s *StandaloneStream) ResetPort() bool {
+	if s.preferedPort != 0 {
+		return false
+	}
+
+	releasePort(s.ports, s.preferedIP, s.port)
+	s.port = 0
+
+	return true
+}
+
+// Pause suspends the process group of the stream with SIGSTOP.
+func (s *StandaloneStream) Pause(ctx context.Context) error {
+	if err := pauseProcessGroup(s.process); err != nil {
+		return fmt.Errorf("could not pause stream process: %v", err)
+	}
+	s.setPaused(true)
+
+	return nil
+}
+
+// Resume continues the suspended process group of the stream with SIGCONT.
+func (s *StandaloneStream) Resume(ctx context.Context) error {
+	if err := resumeProcessGroup(s.process); err != nil {
+		return fmt.Errorf("could not resume stream process: %v", err)
+	}
+	s.setPaused(false)
+
+	return nil
+}
+
+// InterruptNotification notifies when the stream process exits without being stopped.
+//
+// It returns nil if the process has exited successfully.
 func (s *StandaloneStream) InterruptNotification() <-chan error {
 	return s.interruptChan
 }
 
+// Logs returns reader of the stream process stdout and stderr output.
+//
+// The output of the current run can be read only once and must be read
+// continuously, otherwise the stream process is blocked on writing it.
+func (s *StandaloneStream) Logs(ctx context.Context) (io.ReadCloser, error) {
+	if s.logReader == nil {
+		return nil, errors.New("stream process output is not available")
+	}
+
+	return s.logReader, nil
+}
+
+// kill kills the process group of the stream which failed to start.
+func (s *StandaloneStream) kill() {
+	s.setStopped(true)
+	if err := killProcessGroup(s.process); err != nil {
+		logrus.Errorf("could not kill stream process %d: %v", s.process.Pid, err)
+	}
+}
+
+func (s *StandaloneStream) writePIDFile() error {
+	if s.pidFile == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.pidFile), 0700); err != nil {
+		return fmt.Errorf("could not create PID file folder: %v", err)
+	}
+
+	pid := strconv.Itoa(s.process.Pid)
+	if err := ioutil.WriteFile(s.pidFile, []byte(pid), 0600); err != nil {
+		return fmt.Errorf("could not write %s PID file: %v", s.pidFile, err)
+	}
+
+	return nil
+}
+
+func (s *StandaloneStream) removePIDFile() error {
+	if s.pidFile == "" {
+		return nil
+	}
+
+	if err := os.Remove(s.pidFile); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("could not remove %s PID file: %v", s.pidFile, err)
+	}
+
+	return nil
+}
+
+func (s *StandaloneStream) setStopped(stopped bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.stopped = stopped
+}
+
+func (s *StandaloneStream) isStopped() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.stopped
+}
+
+func (s *StandaloneStream) setPaused(paused bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.paused = paused
+}
+
+func (s *StandaloneStream) isPaused() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.paused
+}
+
 func resolveBinPath(binFolder, binName string) string {
 	if runtime.GOOS == "windows" {
 		binName += ".exe"
@@ -94,3 +379,7 @@ func resolveBinPath(binFolder, binName string) string {
 
 	return path.Join(binFolder, binName)
 }
+
+func isEmptyResourceLimits(limits service.StreamResourceLimits) bool {
+	return limits.CPU <= 0 && limits.Memory <= 0 && limits.PIDs <= 0
+}
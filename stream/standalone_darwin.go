@@ -0,0 +1,38 @@
+//go:build darwin
+
+package stream
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// setProcessGroup makes the stream process a leader of its own process group,
+// so the whole group can be signaled at once.
+func setProcessGroup(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setpgid = true
+}
+
+// isStreamProcess reports whether the process is alive and runs the stream binary.
+//
+// There is no procfs on darwin, so the executable path of the process is taken from ps.
+func isStreamProcess(p *os.Process, binPath string) bool {
+	out, err := exec.Command("ps", "-o", "comm=", "-p", strconv.Itoa(p.Pid)).Output()
+	if err != nil {
+		return false
+	}
+
+	binPath, err = filepath.Abs(binPath)
+	if err != nil {
+		return false
+	}
+
+	return strings.TrimSpace(string(out)) == binPath
+}
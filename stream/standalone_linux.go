@@ -0,0 +1,37 @@
+//go:build linux
+
+package stream
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"syscall"
+)
+
+// setProcessGroup makes the stream process a leader of its own process group,
+// so the whole group can be signaled at once. The process is killed as well
+// if the server dies.
+func setProcessGroup(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setpgid = true
+	cmd.SysProcAttr.Pdeathsig = syscall.SIGKILL
+}
+
+// isStreamProcess reports whether the process is alive and runs the stream binary.
+func isStreamProcess(p *os.Process, binPath string) bool {
+	exePath, err := os.Readlink(filepath.Join("/proc", strconv.Itoa(p.Pid), "exe"))
+	if err != nil {
+		return false
+	}
+
+	binPath, err = filepath.Abs(binPath)
+	if err != nil {
+		return false
+	}
+
+	return exePath == binPath
+}
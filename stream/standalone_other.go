@@ -0,0 +1,26 @@
+//go:build aix || dragonfly || freebsd || netbsd || openbsd || solaris
+
+package stream
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// setProcessGroup makes the stream process a leader of its own process group,
+// so the whole group can be signaled at once.
+func setProcessGroup(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setpgid = true
+}
+
+// isStreamProcess reports whether the process is alive and runs the stream binary.
+//
+// The binary of the process isn't identified on this platform, so the process is never
+// treated as the stream one to avoid killing a foreign process which has reused the PID.
+func isStreamProcess(p *os.Process, binPath string) bool {
+	return false
+}
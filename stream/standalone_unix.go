@@ -0,0 +1,43 @@
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+
+package stream
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+const (
+	defaultProcessPollInterval = 100 * time.Millisecond
+)
+
+func terminateProcess(p *os.Process) error {
+	return p.Signal(syscall.SIGTERM)
+}
+
+func killProcessGroup(p *os.Process) error {
+	err := syscall.Kill(-p.Pid, syscall.SIGKILL)
+	if err == syscall.ESRCH {
+		return nil
+	}
+
+	return err
+}
+
+func pauseProcessGroup(p *os.Process) error {
+	return syscall.Kill(-p.Pid, syscall.SIGSTOP)
+}
+
+func resumeProcessGroup(p *os.Process) error {
+	return syscall.Kill(-p.Pid, syscall.SIGCONT)
+}
+
+// waitProcess waits for the process which isn't a child of the current one to exit.
+func waitProcess(p *os.Process, exited chan struct{}) {
+	defer close(exited)
+
+	for p.Signal(syscall.Signal(0)) == nil {
+		time.Sleep(defaultProcessPollInterval)
+	}
+}
@@ -0,0 +1,51 @@
+//go:build windows
+
+package stream
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+)
+
+var errPauseNotSupported = errors.New("pausing stream processes is not supported on windows")
+
+func setProcessGroup(cmd *exec.Cmd) {}
+
+// terminateProcess kills the process since windows doesn't support SIGTERM.
+func terminateProcess(p *os.Process) error {
+	return p.Kill()
+}
+
+func killProcessGroup(p *os.Process) error {
+	err := p.Kill()
+	if err == os.ErrProcessDone {
+		return nil
+	}
+
+	return err
+}
+
+func pauseProcessGroup(p *os.Process) error {
+	return errPauseNotSupported
+}
+
+func resumeProcessGroup(p *os.Process) error {
+	return errPauseNotSupported
+}
+
+// isStreamProcess reports whether the process is alive and runs the stream binary.
+//
+// The binary of the process can't be identified reliably on windows, so the
+// process is never treated as the stream one to avoid killing a foreign process
+// which has reused the PID.
+func isStreamProcess(p *os.Process, binPath string) bool {
+	return false
+}
+
+// waitProcess waits for the process which isn't a child of the current one to exit.
+func waitProcess(p *os.Process, exited chan struct{}) {
+	defer close(exited)
+
+	p.Wait()
+}
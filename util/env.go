@@ -0,0 +1,43 @@
+package util
+
+import "strings"
+
+var (
+	// unsafeEnvNames are environment variables changing how the process resolves
+	// and loads binaries, libraries or shell startup files.
+	unsafeEnvNames = map[string]struct{}{
+		"PATH":        {},
+		"IFS":         {},
+		"ENV":         {},
+		"BASH_ENV":    {},
+		"SHELLOPTS":   {},
+		"GCONV_PATH":  {},
+		"LOCPATH":     {},
+		"NLSPATH":     {},
+		"HOSTALIASES": {},
+		"RES_OPTIONS": {},
+		"LOCALDOMAIN": {},
+	}
+	// unsafeEnvPrefixes are prefixes of the dynamic linker environment variables.
+	unsafeEnvPrefixes = []string{
+		"LD_",
+		"DYLD_",
+	}
+)
+
+// IsUnsafeEnv reports whether the environment variable may hijack the process
+// started with it, e.g. by preloading a library or changing the binary lookup path.
+func IsUnsafeEnv(name string) bool {
+	name = strings.ToUpper(name)
+	if _, ok := unsafeEnvNames[name]; ok {
+		return true
+	}
+
+	for _, prefix := range unsafeEnvPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
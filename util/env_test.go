@@ -0,0 +1,23 @@
+package util
+
+import "testing"
+
+func TestIsUnsafeEnv(t *testing.T) {
+	tests := map[string]bool{
+		"LD_PRELOAD":            true,
+		"LD_LIBRARY_PATH":       true,
+		"ld_preload":            true,
+		"DYLD_INSERT_LIBRARIES": true,
+		"PATH":                  true,
+		"BASH_ENV":              true,
+		"STREAM_THEME":          false,
+		"LANG":                  false,
+		"OLD_PATH":              false,
+	}
+
+	for name, want := range tests {
+		if got := IsUnsafeEnv(name); got != want {
+			t.Errorf("IsUnsafeEnv(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
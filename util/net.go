@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"strings"
 )
 
 // FreePort returns free system open port that is ready to use.
@@ -21,11 +22,79 @@ func FreePort(host string) (int, error) {
 	return tcpListener.Addr().(*net.TCPAddr).Port, tcpListener.Close()
 }
 
+// ParseTrustedProxies parses IP addresses and CIDR ranges of the trusted proxies.
+func ParseTrustedProxies(proxies []string) ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, 0, len(proxies))
+	for _, proxy := range proxies {
+		proxy = strings.TrimSpace(proxy)
+		if !strings.Contains(proxy, "/") {
+			ip := net.ParseIP(proxy)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid proxy IP %s", proxy)
+			}
+
+			bits := 8 * net.IPv4len
+			if ip.To4() == nil {
+				bits = 8 * net.IPv6len
+			}
+			nets = append(nets, &net.IPNet{
+				IP:   ip,
+				Mask: net.CIDRMask(bits, bits),
+			})
+			continue
+		}
+
+		_, ipNet, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy range %s: %v", proxy, err)
+		}
+		nets = append(nets, ipNet)
+	}
+
+	return nets, nil
+}
+
 // GetIP returns IP address of the request.
-func GetIP(r *http.Request) string {
-	if forwarded := r.Header.Get("X-FORWARDED-FOR"); forwarded != "" {
-		return forwarded
+//
+// X-Forwarded-For header is honored only for the requests sent by the trusted proxies,
+// the first address that isn't a trusted proxy is taken from the end of the chain.
+func GetIP(r *http.Request, trustedProxies []*net.IPNet) string {
+	ip := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+
+	if !isTrustedProxy(ip, trustedProxies) {
+		return ip
+	}
+
+	forwarded := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		forwardedIP := strings.TrimSpace(forwarded[i])
+		if net.ParseIP(forwardedIP) == nil {
+			break
+		}
+
+		ip = forwardedIP
+		if !isTrustedProxy(ip, trustedProxies) {
+			break
+		}
+	}
+
+	return ip
+}
+
+func isTrustedProxy(ip string, trustedProxies []*net.IPNet) bool {
+	parsedIP := net.ParseIP(ip)
+	if parsedIP == nil {
+		return false
+	}
+
+	for _, proxy := range trustedProxies {
+		if proxy.Contains(parsedIP) {
+			return true
+		}
 	}
 
-	return r.RemoteAddr
+	return false
 }
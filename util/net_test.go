@@ -0,0 +1,81 @@
+package util
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseTrustedProxies(t *testing.T) {
+	if _, err := ParseTrustedProxies([]string{"10.0.0.1", "192.168.0.0/16", "::1", "fd00::/8"}); err != nil {
+		t.Fatalf("could not parse trusted proxies: %v", err)
+	}
+
+	for _, proxy := range []string{"proxy.local", "10.0.0.0/33", ""} {
+		if _, err := ParseTrustedProxies([]string{proxy}); err == nil {
+			t.Errorf("invalid proxy %q is parsed", proxy)
+		}
+	}
+}
+
+func TestGetIP(t *testing.T) {
+	proxies, err := ParseTrustedProxies([]string{"10.0.0.1", "192.168.0.0/16"})
+	if err != nil {
+		t.Fatalf("could not parse trusted proxies: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		forwarded  string
+		want       string
+	}{
+		{
+			name:       "direct request",
+			remoteAddr: "203.0.113.5:51000",
+			want:       "203.0.113.5",
+		},
+		{
+			name:       "forwarded header of untrusted client",
+			remoteAddr: "203.0.113.5:51000",
+			forwarded:  "198.51.100.7",
+			want:       "203.0.113.5",
+		},
+		{
+			name:       "trusted proxy",
+			remoteAddr: "10.0.0.1:51000",
+			forwarded:  "198.51.100.7",
+			want:       "198.51.100.7",
+		},
+		{
+			name:       "spoofed chain behind trusted proxies",
+			remoteAddr: "10.0.0.1:51000",
+			forwarded:  "1.2.3.4, 198.51.100.7, 192.168.1.10",
+			want:       "198.51.100.7",
+		},
+		{
+			name:       "invalid forwarded address",
+			remoteAddr: "10.0.0.1:51000",
+			forwarded:  "unknown",
+			want:       "10.0.0.1",
+		},
+		{
+			name:       "trusted proxy without header",
+			remoteAddr: "10.0.0.1:51000",
+			want:       "10.0.0.1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			r.RemoteAddr = tt.remoteAddr
+			if tt.forwarded != "" {
+				r.Header.Set("X-Forwarded-For", tt.forwarded)
+			}
+
+			if ip := GetIP(r, proxies); ip != tt.want {
+				t.Errorf("unexpected IP: got %s, want %s", ip, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	defaultFreePortRetryCount = 10
+)
+
+// PortPool represents pool of ports reserved per IP.
+//
+// Reserved ports aren't handed out again until they are released, even if
+// nothing is bound to them yet. Ports bound by other processes are skipped.
+// Port reserved on the wildcard IP, e.g. 0.0.0.0, is reserved on every IP.
+type PortPool struct {
+	min      int
+	max      int
+	mu       sync.Mutex
+	reserved map[string]map[int]string
+	next     map[string]int
+}
+
+// NewPortPool returns new pool of the ports within [min, max] range.
+//
+// If the range is empty, ports are assigned by the system.
+func NewPortPool(min, max int) *PortPool {
+	return &PortPool{
+		min:      min,
+		max:      max,
+		reserved: make(map[string]map[int]string),
+		next:     make(map[string]int),
+	}
+}
+
+// ParsePortRange parses port range in "min-max" format.
+func ParsePortRange(portRange string) (int, int, error) {
+	bounds := strings.Split(portRange, "-")
+	if len(bounds) != 2 {
+		return 0, 0, fmt.Errorf("invalid port range %q, expected min-max", portRange)
+	}
+
+	min, err := strconv.Atoi(strings.TrimSpace(bounds[0]))
+	if err != nil {
+		return 0, 0, fmt.Errorf("could not parse min port: %v", err)
+	}
+
+	max, err := strconv.Atoi(strings.TrimSpace(bounds[1]))
+	if err != nil {
+		return 0, 0, fmt.Errorf("could not parse max port: %v", err)
+	}
+
+	if min <= 0 || max > 65535 || min > max {
+		return 0, 0, fmt.Errorf("invalid port range %d-%d", min, max)
+	}
+
+	return min, max, nil
+}
+
+// Reserve reserves free port on the IP for the owner.
+func (p *PortPool) Reserve(ip, owner string) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.min == 0 && p.max == 0 {
+		return p.reserveSystemPort(ip, owner)
+	}
+
+	size := p.max - p.min + 1
+	offset := p.next[ip]
+	for i := 0; i < size; i++ {
+		port := p.min + (offset+i)%size
+		if p.isReserved(ip, port) || !isPortFree(ip, port) {
+			continue
+		}
+
+		p.reserve(ip, port, owner)
+		p.next[ip] = (offset + i + 1) % size
+
+		return port, nil
+	}
+
+	return 0, fmt.Errorf("no free ports left in %d-%d range on %s", p.min, p.max, ip)
+}
+
+// ReservePort reserves the specific port on the IP for the owner.
+//
+// An error is returned if the port has been already reserved.
+func (p *PortPool) ReservePort(ip string, port int, owner string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.isReserved(ip, port) {
+		return fmt.Errorf("port %d is already reserved on %s", port, ip)
+	}
+	p.reserve(ip, port, owner)
+
+	return nil
+}
+
+// Release releases the port on the IP.
+func (p *PortPool) Release(ip string, port int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.reserved[ip], port)
+}
+
+// ReleaseOwner releases all of the ports reserved by the owner.
+func (p *PortPool) ReleaseOwner(owner string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, ports := range p.reserved {
+		for port, portOwner := range ports {
+			if portOwner == owner {
+				delete(ports, port)
+			}
+		}
+	}
+}
+
+func (p *PortPool) reserveSystemPort(ip, owner string) (int, error) {
+	for i := 0; i < defaultFreePortRetryCount; i++ {
+		port, err := FreePort(ip)
+		if err != nil {
+			return 0, err
+		}
+
+		if !p.isReserved(ip, port) {
+			p.reserve(ip, port, owner)
+			return port, nil
+		}
+	}
+
+	return 0, errors.New("could not find port which isn't reserved yet")
+}
+
+func (p *PortPool) reserve(ip string, port int, owner string) {
+	if p.reserved[ip] == nil {
+		p.reserved[ip] = make(map[int]string)
+	}
+	p.reserved[ip][port] = owner
+}
+
+// isReserved reports whether the port is reserved on the IP. Ports reserved on the
+// wildcard IP conflict with the ports of every IP and vice versa.
+func (p *PortPool) isReserved(ip string, port int) bool {
+	if _, ok := p.reserved[ip][port]; ok {
+		return true
+	}
+
+	wildcard := isWildcardIP(ip)
+	for reservedIP, ports := range p.reserved {
+		if !wildcard && !isWildcardIP(reservedIP) {
+			continue
+		}
+		if _, ok := ports[port]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isWildcardIP(ip string) bool {
+	parsedIP := net.ParseIP(ip)
+	return ip == "" || (parsedIP != nil && parsedIP.IsUnspecified())
+}
+
+func isPortFree(ip string, port int) bool {
+	listener, err := net.Listen("tcp", net.JoinHostPort(ip, strconv.Itoa(port)))
+	if err != nil {
+		return false
+	}
+	listener.Close()
+
+	return true
+}
@@ -0,0 +1,163 @@
+package util
+
+import (
+	"net"
+	"testing"
+)
+
+func TestParsePortRange(t *testing.T) {
+	tests := []struct {
+		portRange string
+		min       int
+		max       int
+		wantErr   bool
+	}{
+		{portRange: "9000-9100", min: 9000, max: 9100},
+		{portRange: " 9000 - 9000 ", min: 9000, max: 9000},
+		{portRange: "9000", wantErr: true},
+		{portRange: "9100-9000", wantErr: true},
+		{portRange: "0-9000", wantErr: true},
+		{portRange: "9000-70000", wantErr: true},
+		{portRange: "a-b", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		min, max, err := ParsePortRange(tt.portRange)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("invalid port range %q is parsed", tt.portRange)
+			}
+			continue
+		}
+
+		if err != nil || min != tt.min || max != tt.max {
+			t.Errorf("unexpected %q port range: %d-%d, %v", tt.portRange, min, max, err)
+		}
+	}
+}
+
+func TestPortPoolReserve(t *testing.T) {
+	// the first port of the range is bound by another process.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("could not bind port: %v", err)
+	}
+	defer listener.Close()
+	boundPort := listener.Addr().(*net.TCPAddr).Port
+
+	pool := NewPortPool(boundPort, boundPort+3)
+	reserved := make(map[int]string)
+	owners := []string{"first", "second"}
+	for {
+		owner := owners[len(reserved)%len(owners)]
+		port, err := pool.Reserve("127.0.0.1", owner)
+		if err != nil {
+			break
+		}
+
+		if port == boundPort {
+			t.Errorf("bound port %d is reserved", port)
+		}
+		if port < boundPort || port > boundPort+3 {
+			t.Errorf("port %d is out of the range", port)
+		}
+		if _, ok := reserved[port]; ok {
+			t.Fatalf("port %d is reserved twice", port)
+		}
+		reserved[port] = owner
+	}
+	if len(reserved) == 0 {
+		t.Fatal("no ports are reserved")
+	}
+
+	pool.ReleaseOwner("first")
+	port, err := pool.Reserve("127.0.0.1", "third")
+	if err != nil {
+		t.Fatalf("could not reserve released port: %v", err)
+	}
+	if reserved[port] != "first" {
+		t.Errorf("port %d of %q owner is reserved again", port, reserved[port])
+	}
+
+	for p, owner := range reserved {
+		if owner != "second" {
+			continue
+		}
+
+		pool.Release("127.0.0.1", p)
+		if _, err := pool.Reserve("127.0.0.1", "third"); err != nil {
+			t.Errorf("could not reserve released port %d: %v", p, err)
+		}
+		break
+	}
+}
+
+func TestPortPoolSystemPorts(t *testing.T) {
+	pool := NewPortPool(0, 0)
+
+	first, err := pool.Reserve("127.0.0.1", "first")
+	if err != nil {
+		t.Fatalf("could not reserve port: %v", err)
+	}
+	second, err := pool.Reserve("127.0.0.1", "second")
+	if err != nil {
+		t.Fatalf("could not reserve port: %v", err)
+	}
+	if first == second {
+		t.Errorf("port %d is reserved twice", first)
+	}
+}
+
+func TestPortPoolWildcardIP(t *testing.T) {
+	pool := NewPortPool(0, 0)
+
+	if err := pool.ReservePort("0.0.0.0", 9000, "first"); err != nil {
+		t.Fatalf("could not reserve port: %v", err)
+	}
+	for _, ip := range []string{"0.0.0.0", "127.0.0.1", "10.0.0.1", "::"} {
+		if err := pool.ReservePort(ip, 9000, "second"); err == nil {
+			t.Errorf("port reserved on the wildcard IP is reserved on %s", ip)
+		}
+	}
+	if err := pool.ReservePort("127.0.0.1", 9001, "second"); err != nil {
+		t.Errorf("could not reserve another port: %v", err)
+	}
+
+	pool.Release("0.0.0.0", 9000)
+	if err := pool.ReservePort("127.0.0.1", 9000, "second"); err != nil {
+		t.Fatalf("could not reserve released port: %v", err)
+	}
+	for _, ip := range []string{"0.0.0.0", "::"} {
+		if err := pool.ReservePort(ip, 9000, "third"); err == nil {
+			t.Errorf("port reserved on 127.0.0.1 is reserved on %s", ip)
+		}
+	}
+	if err := pool.ReservePort("10.0.0.1", 9000, "third"); err != nil {
+		t.Errorf("could not reserve port on another IP: %v", err)
+	}
+}
+
+func TestPortPoolReserveSkipsPreferredPort(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("could not bind port: %v", err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	pool := NewPortPool(port, port)
+	if err := pool.ReservePort("127.0.0.1", port, "preferred"); err != nil {
+		t.Fatalf("could not reserve preferred port: %v", err)
+	}
+	if err := pool.ReservePort("127.0.0.1", port, "other"); err == nil {
+		t.Error("preferred port is reserved twice")
+	}
+	if reserved, err := pool.Reserve("127.0.0.1", "other"); err == nil {
+		t.Errorf("preferred port %d is handed out", reserved)
+	}
+
+	pool.ReleaseOwner("preferred")
+	if _, err := pool.Reserve("127.0.0.1", "other"); err != nil {
+		t.Errorf("could not reserve released preferred port: %v", err)
+	}
+}
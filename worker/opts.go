@@ -0,0 +1,159 @@
+package worker
+
+import (
+	"time"
+
+	"github.com/code-cord/cc.core.server/service"
+	"github.com/sirupsen/logrus"
+)
+
+// Option represents set worker option type.
+type Option func(*Options)
+
+// Options represents worker options model.
+type Options struct {
+	Name                  string
+	Version               string
+	Address               string
+	TLSCertFile           string
+	TLSKeyFile            string
+	Token                 string
+	DataFolder            string
+	LogLevel              string
+	LaunchMode            service.StreamLaunchMode
+	Capacity              int
+	BinFolder             string
+	StreamImage           string
+	StreamContainerPrefix string
+	StreamIP              string
+	StreamStopTimeout     time.Duration
+	StreamCgroupParent    string
+	StreamPortRange       string
+
+	logLevel   logrus.Level
+	tlsEnabled bool
+	portMin    int
+	portMax    int
+}
+
+// Name sets worker name option.
+func Name(name string) Option {
+	return func(o *Options) {
+		o.Name = name
+	}
+}
+
+// Version sets worker version option.
+func Version(ver string) Option {
+	return func(o *Options) {
+		o.Version = ver
+	}
+}
+
+// Address sets worker serve address option.
+func Address(addr string) Option {
+	return func(o *Options) {
+		o.Address = addr
+	}
+}
+
+// TLS sets worker TLS option.
+func TLS(certFile, keyFile string) Option {
+	return func(o *Options) {
+		o.TLSCertFile = certFile
+		o.TLSKeyFile = keyFile
+	}
+}
+
+// DataFolder sets folder to store worker data.
+func DataFolder(folder string) Option {
+	return func(o *Options) {
+		o.DataFolder = folder
+	}
+}
+
+// Token sets token shared with the server to authorize worker API requests.
+func Token(token string) Option {
+	return func(o *Options) {
+		o.Token = token
+	}
+}
+
+// LogLevel sets worker log level option.
+func LogLevel(level string) Option {
+	return func(o *Options) {
+		o.LogLevel = level
+	}
+}
+
+// LaunchMode sets mode the worker launches streams in.
+//
+// Only standalone app and docker container modes are supported.
+func LaunchMode(mode service.StreamLaunchMode) Option {
+	return func(o *Options) {
+		o.LaunchMode = mode
+	}
+}
+
+// Capacity sets max number of streams run by the worker at the same time.
+//
+// Zero capacity means the number of streams isn't limited.
+func Capacity(capacity int) Option {
+	return func(o *Options) {
+		o.Capacity = capacity
+	}
+}
+
+// BinFolder sets path to the code-cord binaries.
+func BinFolder(folder string) Option {
+	return func(o *Options) {
+		o.BinFolder = folder
+	}
+}
+
+// StreamImage sets stream docker image name.
+func StreamImage(img string) Option {
+	return func(o *Options) {
+		o.StreamImage = img
+	}
+}
+
+// StreamContainerPrefix sets stream container prefix for streams running inside docker containers.
+func StreamContainerPrefix(prefix string) Option {
+	return func(o *Options) {
+		o.StreamContainerPrefix = prefix
+	}
+}
+
+// StreamIP sets IP the streams are bound to unless the stream prefers another one.
+//
+// The IP must be reachable by the server.
+func StreamIP(ip string) Option {
+	return func(o *Options) {
+		o.StreamIP = ip
+	}
+}
+
+// StreamStopTimeout sets grace period for the stream to exit after being asked to stop.
+func StreamStopTimeout(timeout time.Duration) Option {
+	return func(o *Options) {
+		o.StreamStopTimeout = timeout
+	}
+}
+
+// StreamCgroupParent sets parent cgroup of the standalone streams.
+func StreamCgroupParent(parent string) Option {
+	return func(o *Options) {
+		o.StreamCgroupParent = parent
+	}
+}
+
+// StreamPortRange sets range of the ports in "min-max" format the streams are bound to
+// unless the stream prefers a specific port.
+//
+// If the range is empty, ports are assigned by the system.
+func StreamPortRange(portRange string) Option {
+	return func(o *Options) {
+		o.StreamPortRange = portRange
+	}
+}
@@ -0,0 +1,24 @@
+package worker
+
+import "github.com/code-cord/cc.core.server/util"
+
+// streamPortReserver reserves ports of the worker port pool on behalf of the stream.
+type streamPortReserver struct {
+	pool       *util.PortPool
+	streamUUID string
+}
+
+// Reserve reserves free port on the IP.
+func (r *streamPortReserver) Reserve(ip string) (int, error) {
+	return r.pool.Reserve(ip, r.streamUUID)
+}
+
+// ReservePort reserves the specific port on the IP.
+func (r *streamPortReserver) ReservePort(ip string, port int) error {
+	return r.pool.ReservePort(ip, port, r.streamUUID)
+}
+
+// Release releases the port on the IP.
+func (r *streamPortReserver) Release(ip string, port int) {
+	r.pool.Release(ip, port)
+}
@@ -0,0 +1,133 @@
+package worker
+
+import (
+	"context"
+	"io"
+	"os"
+	"sync"
+)
+
+const (
+	defaultStreamLogBacklog = 1 << 20
+)
+
+// streamLog represents output of the worker stream.
+//
+// The output is drained as soon as the stream starts, so the stream is never blocked
+// on writing it while nobody follows the logs. Only the latest output up to the backlog
+// size is kept for the followers.
+type streamLog struct {
+	mu      sync.Mutex
+	buf     []byte
+	offset  int64
+	backlog int
+	closed  bool
+	written chan struct{}
+}
+
+// streamLogReader represents follower of the worker stream output.
+type streamLogReader struct {
+	ctx context.Context
+	log *streamLog
+	pos int64
+}
+
+func newStreamLog(backlog int) *streamLog {
+	return &streamLog{
+		backlog: backlog,
+		written: make(chan struct{}),
+	}
+}
+
+// capture drains the stream output until it's closed.
+func (l *streamLog) capture(r io.ReadCloser) {
+	defer r.Close()
+	defer l.Close()
+
+	io.Copy(l, r)
+}
+
+// Write appends data to the log dropping the output exceeding the backlog.
+func (l *streamLog) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed {
+		return 0, os.ErrClosed
+	}
+
+	// the output is dropped in batches, so the backlog isn't shifted on every write.
+	l.buf = append(l.buf, p...)
+	if extra := len(l.buf) - l.backlog; extra > l.backlog {
+		l.buf = append(l.buf[:0], l.buf[extra:]...)
+		l.offset += int64(extra)
+	}
+
+	close(l.written)
+	l.written = make(chan struct{})
+
+	return len(p), nil
+}
+
+// Close marks the end of the stream output.
+func (l *streamLog) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.closed {
+		l.closed = true
+		close(l.written)
+	}
+
+	return nil
+}
+
+// reader returns follower of the stream output starting with the kept backlog.
+func (l *streamLog) reader(ctx context.Context) io.ReadCloser {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return &streamLogReader{
+		ctx: ctx,
+		log: l,
+		pos: l.offset,
+	}
+}
+
+// Read reads the stream output waiting for the new one until the stream exits.
+func (r *streamLogReader) Read(p []byte) (int, error) {
+	for {
+		r.log.mu.Lock()
+		// the output dropped from the backlog is skipped.
+		if r.pos < r.log.offset {
+			r.pos = r.log.offset
+		}
+
+		if start := int(r.pos - r.log.offset); start < len(r.log.buf) {
+			n := copy(p, r.log.buf[start:])
+			r.pos += int64(n)
+			r.log.mu.Unlock()
+
+			return n, nil
+		}
+
+		if r.log.closed {
+			r.log.mu.Unlock()
+
+			return 0, io.EOF
+		}
+		written := r.log.written
+		r.log.mu.Unlock()
+
+		select {
+		case <-written:
+		case <-r.ctx.Done():
+			return 0, r.ctx.Err()
+		}
+	}
+}
+
+// Close closes the follower.
+func (r *streamLogReader) Close() error {
+	return nil
+}
@@ -0,0 +1,83 @@
+package worker
+
+import (
+	"context"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStreamLogDrainsWithoutReaders(t *testing.T) {
+	l := newStreamLog(4)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		l.capture(ioutil.NopCloser(strings.NewReader(strings.Repeat("x", 1<<16))))
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("stream output isn't drained without readers")
+	}
+
+	if len(l.buf) > 2*l.backlog {
+		t.Errorf("backlog isn't limited: %d bytes are kept", len(l.buf))
+	}
+}
+
+func TestStreamLogReaderFollowsOutput(t *testing.T) {
+	l := newStreamLog(defaultStreamLogBacklog)
+	l.Write([]byte("first "))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	r := l.reader(ctx)
+
+	go func() {
+		l.Write([]byte("second"))
+		l.Close()
+	}()
+
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatalf("could not read stream log: %v", err)
+	}
+	if string(data) != "first second" {
+		t.Errorf("unexpected stream log: %q", data)
+	}
+
+	if _, err := l.Write([]byte("late")); err == nil {
+		t.Error("closed stream log is written")
+	}
+}
+
+func TestStreamLogReaderSkipsDroppedOutput(t *testing.T) {
+	l := newStreamLog(2)
+	r := l.reader(context.Background())
+
+	l.Write([]byte("abcdef"))
+	l.Close()
+
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatalf("could not read stream log: %v", err)
+	}
+	if string(data) != "ef" {
+		t.Errorf("unexpected stream log: %q", data)
+	}
+}
+
+func TestStreamLogReaderIsCanceled(t *testing.T) {
+	l := newStreamLog(defaultStreamLogBacklog)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r := l.reader(ctx)
+	cancel()
+
+	if _, err := r.Read(make([]byte, 1)); err != context.Canceled {
+		t.Errorf("unexpected read error: %v", err)
+	}
+}
@@ -0,0 +1,464 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"sync"
+
+	workerhandler "github.com/code-cord/cc.core.server/handler/worker"
+	"github.com/code-cord/cc.core.server/service"
+	"github.com/code-cord/cc.core.server/stream"
+	"github.com/code-cord/cc.core.server/util"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultWorkerAddress      = "127.0.0.1:8990"
+	defaultWorkerFolder       = ".worker"
+	defaultStreamCgroupParent = "code-cord"
+	defaultStreamPIDFolder    = "pid"
+	pidFileExt                = ".pid"
+)
+
+// Worker represents stream worker implementation model.
+//
+// Worker launches streams on behalf of the server and exposes them through
+// the authenticated HTTP API.
+type Worker struct {
+	opts       Options
+	httpServer *http.Server
+	streams    *sync.Map
+	ports      *util.PortPool
+	mu         sync.Mutex
+}
+
+type workerStream struct {
+	service.Stream
+	startInfo *service.StartStreamInfo
+	logs      *streamLog
+	stopped   chan struct{}
+	exited    chan struct{}
+	exitErr   error
+	exitOnce  sync.Once
+}
+
+// New returns new worker instance.
+func New(opt ...Option) (*Worker, error) {
+	opts, err := newWorkerOptions(opt...)
+	if err != nil {
+		return nil, fmt.Errorf("could not init worker: %v", err)
+	}
+
+	w := Worker{
+		opts: *opts,
+		httpServer: &http.Server{
+			Addr: opts.Address,
+		},
+		streams: new(sync.Map),
+		ports:   util.NewPortPool(opts.portMin, opts.portMax),
+	}
+	if opts.LogLevel != "" {
+		logrus.SetLevel(opts.logLevel)
+	}
+	w.httpServer.Handler = workerhandler.New(workerhandler.Config{
+		Worker: &w,
+		Token:  opts.Token,
+	})
+
+	return &w, nil
+}
+
+// Run runs worker.
+func (w *Worker) Run(ctx context.Context) error {
+	// stop streams left running by the previous worker run.
+	if err := w.stopStreamStragglers(ctx); err != nil {
+		logrus.Errorf("could not stop stream stragglers: %v", err)
+	}
+
+	logrus.Infof("starting worker at %s", w.httpServer.Addr)
+
+	var err error
+	if w.opts.tlsEnabled {
+		err = w.httpServer.ListenAndServeTLS(w.opts.TLSCertFile, w.opts.TLSKeyFile)
+	} else {
+		err = w.httpServer.ListenAndServe()
+	}
+	if err != http.ErrServerClosed {
+		return err
+	}
+
+	return nil
+}
+
+// Stop stops all of the worker streams and the worker itself.
+func (w *Worker) Stop(ctx context.Context) error {
+	w.streams.Range(func(key, value interface{}) bool {
+		if err := w.StopStream(ctx, key.(string)); err != nil {
+			logrus.Errorf("could not stop %s stream: %v", key, err)
+		}
+
+		return true
+	})
+
+	return w.httpServer.Shutdown(ctx)
+}
+
+// Info returns worker info.
+func (w *Worker) Info() service.WorkerInfo {
+	return service.WorkerInfo{
+		Name:       w.opts.Name,
+		Version:    w.opts.Version,
+		LaunchMode: w.opts.LaunchMode,
+		Capacity:   w.opts.Capacity,
+		Streams:    w.streamCount(),
+	}
+}
+
+// StartStream starts a new stream.
+func (w *Worker) StartStream(
+	ctx context.Context, streamUUID string, cfg service.StreamLaunchConfig) (
+	*service.StartStreamInfo, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if _, ok := w.streams.Load(streamUUID); ok {
+		return nil, fmt.Errorf("stream %s is already running", streamUUID)
+	}
+
+	if w.opts.Capacity > 0 && w.streamCount() >= w.opts.Capacity {
+		return nil, service.ErrWorkerFull
+	}
+
+	s, err := w.newStream(streamUUID, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	startInfo, err := s.Start(ctx)
+	if err != nil {
+		w.ports.ReleaseOwner(streamUUID)
+		return nil, fmt.Errorf("could not run stream instance: %v", err)
+	}
+
+	ws := &workerStream{
+		Stream:    s,
+		startInfo: startInfo,
+		logs:      newStreamLog(defaultStreamLogBacklog),
+		stopped:   make(chan struct{}),
+		exited:    make(chan struct{}),
+	}
+	w.streams.Store(streamUUID, ws)
+
+	if logs, err := s.Logs(context.Background()); err != nil {
+		logrus.Warnf("could not capture %s stream output: %v", streamUUID, err)
+		ws.logs.Close()
+	} else {
+		go ws.logs.capture(logs)
+	}
+
+	go w.watchStream(streamUUID, ws)
+
+	logrus.Infof("stream %s has been started at %s:%d", streamUUID, startInfo.IP, startInfo.Port)
+
+	return startInfo, nil
+}
+
+// WorkerStream returns info of the stream run by the worker.
+func (w *Worker) WorkerStream(ctx context.Context, streamUUID string) (
+	*service.StartStreamInfo, error) {
+	ws, err := w.stream(streamUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	return ws.startInfo, nil
+}
+
+// StopStream stops running stream.
+func (w *Worker) StopStream(ctx context.Context, streamUUID string) error {
+	value, ok := w.streams.LoadAndDelete(streamUUID)
+	if !ok {
+		return os.ErrNotExist
+	}
+
+	ws := value.(*workerStream)
+	close(ws.stopped)
+	err := ws.Stop(ctx)
+	ws.exit(nil)
+	w.ports.ReleaseOwner(streamUUID)
+
+	logrus.Infof("stream %s has been stopped", streamUUID)
+
+	return err
+}
+
+// PauseStream pauses running stream.
+func (w *Worker) PauseStream(ctx context.Context, streamUUID string) error {
+	ws, err := w.stream(streamUUID)
+	if err != nil {
+		return err
+	}
+
+	return ws.Pause(ctx)
+}
+
+// ResumeStream resumes paused stream.
+func (w *Worker) ResumeStream(ctx context.Context, streamUUID string) error {
+	ws, err := w.stream(streamUUID)
+	if err != nil {
+		return err
+	}
+
+	return ws.Resume(ctx)
+}
+
+// StreamLogs returns reader of the stream output.
+//
+// The reader starts with the latest output kept by the worker and follows the output
+// until the stream exits.
+func (w *Worker) StreamLogs(ctx context.Context, streamUUID string) (io.ReadCloser, error) {
+	ws, err := w.stream(streamUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	return ws.logs.reader(ctx), nil
+}
+
+// WaitStream waits for the stream to exit.
+func (w *Worker) WaitStream(ctx context.Context, streamUUID string) (
+	*service.WorkerStreamExit, error) {
+	ws, err := w.stream(streamUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	select {
+	case <-ws.exited:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	var exit service.WorkerStreamExit
+	if ws.exitErr != nil {
+		exit.Error = ws.exitErr.Error()
+	}
+
+	return &exit, nil
+}
+
+func (w *Worker) newStream(streamUUID string, cfg service.StreamLaunchConfig) (
+	service.Stream, error) {
+	preferredIP := cfg.PreferredIP
+	if preferredIP == "" {
+		preferredIP = w.opts.StreamIP
+	}
+	ports := &streamPortReserver{
+		pool:       w.ports,
+		streamUUID: streamUUID,
+	}
+
+	switch w.opts.LaunchMode {
+	case service.StreamLaunchModeStandaloneApp:
+		return stream.NewStandaloneStream(stream.StandaloneStreamConfig{
+			PreferedIP:   preferredIP,
+			PreferedPort: cfg.PreferredPort,
+			BinPath:      w.opts.BinFolder,
+			StopTimeout:  w.opts.StreamStopTimeout,
+			PIDFile:      w.streamPIDFile(streamUUID),
+			Limits:       cfg.Limits,
+			CgroupParent: w.opts.StreamCgroupParent,
+			Ports:        ports,
+			Env:          cfg.Env,
+			Args:         cfg.Args,
+		}), nil
+	case service.StreamLaunchModeDockerContainer:
+		image := cfg.Image
+		if image == "" {
+			image = w.opts.StreamImage
+		}
+
+		return stream.NewDockerContainerStream(stream.DockerContainerStreamConfig{
+			StreamUUID:      streamUUID,
+			ContainerPrefix: w.opts.StreamContainerPrefix,
+			DockerImage:     image,
+			PreferedPort:    cfg.PreferredPort,
+			PreferedIP:      preferredIP,
+			StopTimeout:     w.opts.StreamStopTimeout,
+			Limits:          cfg.Limits,
+			Ports:           ports,
+			Env:             cfg.Env,
+			Args:            cfg.Args,
+		}), nil
+	}
+
+	return nil, fmt.Errorf("invalid launch mode: %v", w.opts.LaunchMode)
+}
+
+// watchStream forgets the stream once it exits without being stopped.
+func (w *Worker) watchStream(streamUUID string, ws *workerStream) {
+	select {
+	case err := <-ws.InterruptNotification():
+		logrus.Warnf("stream %s has exited: %v", streamUUID, err)
+
+		w.streams.Delete(streamUUID)
+		ws.exit(err)
+		w.ports.ReleaseOwner(streamUUID)
+	case <-ws.stopped:
+	}
+}
+
+// stopStreamStragglers stops processes of the standalone streams left running by
+// the previous worker run.
+func (w *Worker) stopStreamStragglers(ctx context.Context) error {
+	files, err := ioutil.ReadDir(path.Join(w.opts.DataFolder, defaultStreamPIDFolder))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		return fmt.Errorf("could not read PID files: %v", err)
+	}
+
+	for _, file := range files {
+		if file.IsDir() || path.Ext(file.Name()) != pidFileExt {
+			continue
+		}
+
+		streamUUID := strings.TrimSuffix(file.Name(), pidFileExt)
+		pidFile := w.streamPIDFile(streamUUID)
+
+		straggler, err := stream.FindStandaloneStream(stream.StandaloneStreamConfig{
+			BinPath:     w.opts.BinFolder,
+			StopTimeout: w.opts.StreamStopTimeout,
+			PIDFile:     pidFile,
+		})
+		switch {
+		case err == nil:
+			logrus.Infof("stopping straggler process of %s stream", streamUUID)
+			if err := straggler.Stop(ctx); err != nil {
+				logrus.Errorf("could not stop straggler process of %s stream: %v", streamUUID, err)
+			}
+		case errors.Is(err, os.ErrNotExist):
+			if err := os.Remove(pidFile); err != nil && !os.IsNotExist(err) {
+				logrus.Errorf("could not remove stale %s PID file: %v", pidFile, err)
+			}
+		default:
+			logrus.Errorf("could not find straggler process of %s stream: %v", streamUUID, err)
+		}
+	}
+
+	return nil
+}
+
+func (w *Worker) streamPIDFile(streamUUID string) string {
+	return path.Join(w.opts.DataFolder, defaultStreamPIDFolder, streamUUID+pidFileExt)
+}
+
+func (w *Worker) stream(streamUUID string) (*workerStream, error) {
+	value, ok := w.streams.Load(streamUUID)
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+
+	return value.(*workerStream), nil
+}
+
+func (w *Worker) streamCount() int {
+	var count int
+	w.streams.Range(func(key, value interface{}) bool {
+		count++
+		return true
+	})
+
+	return count
+}
+
+func (ws *workerStream) exit(err error) {
+	ws.exitOnce.Do(func() {
+		ws.exitErr = err
+		close(ws.exited)
+	})
+}
+
+func newWorkerOptions(opt ...Option) (*Options, error) {
+	var opts Options
+
+	for _, o := range opt {
+		o(&opts)
+	}
+
+	if opts.Address == "" {
+		opts.Address = defaultWorkerAddress
+	}
+
+	if opts.Token == "" {
+		return nil, errors.New("worker token is not set")
+	}
+
+	if opts.TLSCertFile == "" && opts.TLSKeyFile != "" {
+		return nil, errors.New("you must provide the TLS cert file along with the TLS key file")
+	}
+	if opts.TLSCertFile != "" && opts.TLSKeyFile == "" {
+		return nil, errors.New("you must provide the TLS key file along with the TLS cert file")
+	}
+	opts.tlsEnabled = opts.TLSCertFile != "" && opts.TLSKeyFile != ""
+	if !opts.tlsEnabled {
+		logrus.Warn("Worker API is served over plain http! " +
+			"Please specify `--tls-cert` and `--tls-key` flags to protect the worker token")
+	}
+
+	if opts.DataFolder == "" {
+		dir, err := os.Getwd()
+		if err != nil {
+			return nil, fmt.Errorf("could not detect working directory path: %v", err)
+		}
+		opts.DataFolder = path.Join(dir, defaultWorkerFolder)
+	}
+	if err := os.MkdirAll(opts.DataFolder, 0700); err != nil && !os.IsExist(err) {
+		return nil, fmt.Errorf("could not create worker data folder: %v", err)
+	}
+
+	if opts.StreamPortRange != "" {
+		var err error
+		opts.portMin, opts.portMax, err = util.ParsePortRange(opts.StreamPortRange)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse stream port range: %v", err)
+		}
+	}
+
+	if opts.LaunchMode == "" {
+		opts.LaunchMode = service.StreamLaunchModeStandaloneApp
+	}
+	if opts.LaunchMode != service.StreamLaunchModeStandaloneApp &&
+		opts.LaunchMode != service.StreamLaunchModeDockerContainer {
+		return nil, fmt.Errorf("worker can't launch streams in %s mode", opts.LaunchMode)
+	}
+
+	if opts.StreamCgroupParent == "" {
+		opts.StreamCgroupParent = defaultStreamCgroupParent
+	}
+
+	if opts.Capacity < 0 {
+		return nil, errors.New("worker capacity can't be negative")
+	}
+
+	if opts.LogLevel != "" {
+		lvl, err := logrus.ParseLevel(opts.LogLevel)
+		if err != nil {
+			logrus.Errorf("could not set log level: %v", err)
+			logrus.Infof("default log level will be used: \"%s\"", logrus.InfoLevel)
+			lvl = logrus.InfoLevel
+		}
+		opts.logLevel = lvl
+	}
+
+	return &opts, nil
+}